@@ -0,0 +1,51 @@
+package module
+
+import "fmt"
+
+// EstimateGas returns the estimated gas to submit the header.
+func (b *UpdateBudget) EstimateGas(h *Header) uint64 {
+	return b.GetGasPerHeader() + b.GetGasPerByte()*uint64(h.Size())
+}
+
+// fits returns true if the headers of the total size and gas are within the budget.
+func (b *UpdateBudget) fits(size uint64, gas uint64) bool {
+	if b.GetMaxBytes() > 0 && size > b.GetMaxBytes() {
+		return false
+	}
+	if b.GetMaxGas() > 0 && gas > b.GetMaxGas() {
+		return false
+	}
+	return true
+}
+
+// batchBuilder accumulates the size and gas of the headers in a batch.
+// A header can not be split because the light client verifies all its ETH headers at once,
+// so ErrHeaderTooLarge is returned if a single header exceeds the budget.
+type batchBuilder struct {
+	budget  *UpdateBudget
+	headers int
+	size    uint64
+	gas     uint64
+}
+
+// add adds the header to the batch and returns true if the batch is still within the budget.
+// Otherwise the batch is left unchanged and the header must go to the next batch.
+func (b *batchBuilder) add(h *Header) (bool, error) {
+	headerSize, headerGas := uint64(h.Size()), b.budget.EstimateGas(h)
+	if !b.budget.fits(headerSize, headerGas) {
+		return false, fmt.Errorf("%w: height=%d, size=%d, gas=%d, maxBytes=%d, maxGas=%d",
+			ErrHeaderTooLarge, h.GetHeight().GetRevisionHeight(), headerSize, headerGas, b.budget.GetMaxBytes(), b.budget.GetMaxGas())
+	}
+	if b.headers > 0 && !b.budget.fits(b.size+headerSize, b.gas+headerGas) {
+		return false, nil
+	}
+	b.headers++
+	b.size += headerSize
+	b.gas += headerGas
+	return true, nil
+}
+
+// reset empties the batch to start the next one.
+func (b *batchBuilder) reset() {
+	b.headers, b.size, b.gas = 0, 0, 0
+}
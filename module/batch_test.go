@@ -0,0 +1,107 @@
+package module
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type BatchTestSuite struct {
+	suite.Suite
+}
+
+func TestBatchTestSuite(t *testing.T) {
+	suite.Run(t, new(BatchTestSuite))
+}
+
+func (ts *BatchTestSuite) headers(n int) []*Header {
+	var headers []*Header
+	for i := 0; i < n; i++ {
+		ethHeader, err := newETHHeader(&types.Header{Number: big.NewInt(int64(100 + i)), Difficulty: big.NewInt(2)})
+		ts.Require().NoError(err)
+		headers = append(headers, &Header{Headers: []*ETHHeader{ethHeader}})
+	}
+	return headers
+}
+
+// batches adds the headers to the builder in order and returns the number of the headers in each batch.
+func (ts *BatchTestSuite) batches(budget *UpdateBudget, headers []*Header) ([]int, error) {
+	var batches []int
+	builder := &batchBuilder{budget: budget}
+	for _, h := range headers {
+		ok, err := builder.add(h)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			batches = append(batches, builder.headers)
+			builder.reset()
+			ok, err = builder.add(h)
+			ts.Require().NoError(err)
+			ts.Require().True(ok)
+		}
+	}
+	if builder.headers > 0 {
+		batches = append(batches, builder.headers)
+	}
+	return batches, nil
+}
+
+func (ts *BatchTestSuite) TestNoBudget() {
+	headers := ts.headers(3)
+	batches, err := ts.batches(nil, headers)
+	ts.Require().NoError(err)
+	ts.Require().Equal([]int{3}, batches)
+
+	batches, err = ts.batches(nil, nil)
+	ts.Require().NoError(err)
+	ts.Require().Empty(batches)
+}
+
+func (ts *BatchTestSuite) TestMaxBytes() {
+	headers := ts.headers(5)
+	size := uint64(headers[0].Size())
+	batches, err := ts.batches(&UpdateBudget{MaxBytes: 2*size + 1}, headers)
+	ts.Require().NoError(err)
+	ts.Require().Equal([]int{2, 2, 1}, batches)
+
+	_, err = ts.batches(&UpdateBudget{MaxBytes: size - 1}, headers)
+	ts.Require().True(errors.Is(err, ErrHeaderTooLarge))
+}
+
+func (ts *BatchTestSuite) TestMaxGas() {
+	headers := ts.headers(3)
+	budget := &UpdateBudget{GasPerHeader: 100, GasPerByte: 16}
+	gas := budget.EstimateGas(headers[0])
+	ts.Require().Equal(100+16*uint64(headers[0].Size()), gas)
+
+	budget.MaxGas = 3 * gas
+	batches, err := ts.batches(budget, headers)
+	ts.Require().NoError(err)
+	ts.Require().Equal([]int{3}, batches)
+
+	budget.MaxGas = gas
+	batches, err = ts.batches(budget, headers)
+	ts.Require().NoError(err)
+	ts.Require().Equal([]int{1, 1, 1}, batches)
+
+	budget.MaxGas = gas - 1
+	_, err = ts.batches(budget, headers)
+	ts.Require().True(errors.Is(err, ErrHeaderTooLarge))
+}
+
+func (ts *BatchTestSuite) TestRejectedHeaderLeavesBatch() {
+	headers := ts.headers(2)
+	builder := &batchBuilder{budget: &UpdateBudget{MaxBytes: uint64(headers[0].Size()) + 1}}
+	ok, err := builder.add(headers[0])
+	ts.Require().NoError(err)
+	ts.Require().True(ok)
+	ok, err = builder.add(headers[1])
+	ts.Require().NoError(err)
+	ts.Require().False(ok)
+	ts.Require().Equal(1, builder.headers)
+	ts.Require().Equal(uint64(headers[0].Size()), builder.size)
+}
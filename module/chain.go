@@ -2,11 +2,19 @@ package module
 
 import (
 	"context"
+	"fmt"
 	"math/big"
+	"strconv"
+	"time"
 
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
 	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/client"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/hyperledger-labs/yui-relayer/core"
 )
 
@@ -36,6 +44,11 @@ func (c *ethChain) Header(ctx context.Context, height uint64) (*types.Header, er
 	return block.Header(), nil
 }
 
+// FinalizedHeader returns the header of the `finalized` block tag of the node.
+func (c *ethChain) FinalizedHeader(ctx context.Context) (*types.Header, error) {
+	return c.client.HeaderByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+}
+
 func (c *ethChain) IBCAddress() common.Address {
 	return c.ibcAddress
 }
@@ -51,3 +64,73 @@ func (c *ethChain) CanonicalChainID(ctx context.Context) (uint64, error) {
 func (c *ethChain) GetProof(ctx context.Context, address common.Address, storageKeys [][]byte, blockNumber *big.Int) (*client.StateProof, error) {
 	return c.client.GetProof(ctx, address, storageKeys, blockNumber)
 }
+
+// sourceChain reads the self chain through another RPC endpoint while sharing the rest of core.Chain with the base chain.
+type sourceChain struct {
+	Chain
+	client *client.ETHClient
+}
+
+func newSourceChain(base Chain, client *client.ETHClient) Chain {
+	return &sourceChain{Chain: NewChain(base, base.IBCAddress(), client), client: client}
+}
+
+func (c *sourceChain) LatestHeight(ctx context.Context) (exported.Height, error) {
+	number, err := c.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return clienttypes.NewHeight(0, number), nil
+}
+
+// rpcChain reads the self chain only through the RPC endpoint.
+// It is used by the commands that run without the relayer config, so it implements the methods of core.Chain the prover calls
+// and the ones to relay packets, which need the config, are not available.
+type rpcChain struct {
+	*ethChain
+	chainID uint64
+	codec   codec.ProtoCodecMarshaler
+}
+
+func newRPCChain(ctx context.Context, client *client.ETHClient) (Chain, error) {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the chain id: %+v", err)
+	}
+	registry := codectypes.NewInterfaceRegistry()
+	clienttypes.RegisterInterfaces(registry)
+	Module{}.RegisterInterfaces(registry)
+	return &rpcChain{
+		ethChain: &ethChain{client: client},
+		chainID:  chainID.Uint64(),
+		codec:    codec.NewProtoCodec(registry),
+	}, nil
+}
+
+func (c *rpcChain) ChainID() string {
+	return strconv.FormatUint(c.chainID, 10)
+}
+
+func (c *rpcChain) Codec() codec.ProtoCodecMarshaler {
+	return c.codec
+}
+
+func (c *rpcChain) CanonicalChainID(_ context.Context) (uint64, error) {
+	return c.chainID, nil
+}
+
+func (c *rpcChain) LatestHeight(ctx context.Context) (exported.Height, error) {
+	number, err := c.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return clienttypes.NewHeight(0, number), nil
+}
+
+func (c *rpcChain) Timestamp(ctx context.Context, height exported.Height) (time.Time, error) {
+	header, err := c.Header(ctx, height.GetRevisionHeight())
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(int64(MilliTimestamp(header))), nil
+}
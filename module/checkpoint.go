@@ -0,0 +1,208 @@
+package module
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/hyperledger-labs/yui-relayer/log"
+)
+
+// updateCheckpoint is the headers built for the update of a client from the trusted height.
+// It is persisted so that the catch-up resumes from the headers already built after the relayer restarts.
+// The file is in JSON lines: the first line is the checkpoint itself and each following line is a header,
+// so that a header is appended without rewriting the ones before it.
+type updateCheckpoint struct {
+	ClientID      string `json:"client_id"`
+	TrustedHeight uint64 `json:"trusted_height"`
+	// TargetHeight is the height of the latest finalized header the headers were built for
+	TargetHeight uint64 `json:"target_height"`
+	// Headers are the marshaled headers in the order of submission
+	Headers [][]byte `json:"-"`
+
+	path string
+	// saved is true while the file holds the checkpoint, so that the following headers are appended to it
+	saved bool
+}
+
+// checkpointPath returns the path of the checkpoint of the client, or an empty string if the home path is not set.
+func (pr *Prover) checkpointPath(clientID string) string {
+	if pr.homePath == "" {
+		return ""
+	}
+	return filepath.Join(pr.homePath, "checkpoints", "parlia", pr.chain.ChainID(), clientID+".jsonl")
+}
+
+// resumeCheckpoint returns the checkpoint to record the headers built from the trusted height
+// and the headers already built in it which the counterparty client has not stored yet.
+// The checkpoint is resumed only if the trusted height is its trusted height or the height of one of its headers,
+// so the headers are never replayed on a client updated by another relayer from a different height.
+func (pr *Prover) resumeCheckpoint(ctx context.Context, clientID string, trustedHeight exported.Height, target *Header) (*updateCheckpoint, []*Header) {
+	logger := log.GetLogger()
+	path := pr.checkpointPath(clientID)
+	fresh := &updateCheckpoint{
+		ClientID:      clientID,
+		TrustedHeight: trustedHeight.GetRevisionHeight(),
+		TargetHeight:  target.GetHeight().GetRevisionHeight(),
+		path:          path,
+	}
+	if path == "" {
+		return fresh, nil
+	}
+	checkpoint, err := loadCheckpoint(path)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to load the checkpoint", err, "path", path)
+		return fresh, nil
+	}
+	if checkpoint == nil || checkpoint.ClientID != clientID {
+		return fresh, nil
+	}
+	headers, err := checkpoint.resume(trustedHeight, target.GetHeight().GetRevisionHeight())
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to resume the checkpoint", err, "path", path)
+		return fresh, nil
+	}
+	if len(headers) == 0 {
+		return fresh, nil
+	}
+	logger.InfoContext(ctx, "resume the checkpoint", "clientID", clientID, "trusted", trustedHeight.GetRevisionHeight(), "restored", len(headers))
+	checkpoint.TargetHeight = target.GetHeight().GetRevisionHeight()
+	return checkpoint, headers
+}
+
+// resume returns the headers after the trusted height up to the target height with the trusted heights chained,
+// or nil if the trusted height is not in the checkpoint.
+// The checkpoint is rebased on the trusted height so that it holds only the headers not stored by the counterparty client.
+func (c *updateCheckpoint) resume(trustedHeight exported.Height, targetHeight uint64) ([]*Header, error) {
+	matched := c.TrustedHeight == trustedHeight.GetRevisionHeight()
+	var headers []*Header
+	var remaining [][]byte
+	previous := toHeight(trustedHeight)
+	for _, bz := range c.Headers {
+		h := &Header{}
+		if err := h.Unmarshal(bz); err != nil {
+			return nil, err
+		}
+		height := h.GetHeight().GetRevisionHeight()
+		if !matched {
+			matched = height == trustedHeight.GetRevisionHeight()
+			continue
+		}
+		if height > targetHeight {
+			break
+		}
+		trusted := previous
+		h.TrustedHeight = &trusted
+		headers = append(headers, h)
+		remaining = append(remaining, bz)
+		previous = clienttypes.NewHeight(trustedHeight.GetRevisionNumber(), height)
+	}
+	if !matched {
+		return nil, nil
+	}
+	c.TrustedHeight = trustedHeight.GetRevisionHeight()
+	c.Headers = remaining
+	c.saved = false
+	return headers, nil
+}
+
+// append records the header and persists the checkpoint.
+// Only the header is written unless the checkpoint has been changed since it was saved.
+func (c *updateCheckpoint) append(h *Header) error {
+	bz, err := h.Marshal()
+	if err != nil {
+		return err
+	}
+	c.Headers = append(c.Headers, bz)
+	if c.path == "" {
+		return nil
+	}
+	if !c.saved {
+		return c.save()
+	}
+	line, err := json.Marshal(bz)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(c.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (c *updateCheckpoint) save() error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(c); err != nil {
+		return err
+	}
+	for _, bz := range c.Headers {
+		if err := encoder.Encode(bz); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	// The checkpoint is replaced at once so that a restart while writing never leaves a broken one
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return err
+	}
+	c.saved = true
+	return nil
+}
+
+// loadCheckpoint returns the checkpoint at the path, or nil if it does not exist.
+// The last header is dropped if a restart while appending left it incomplete.
+func loadCheckpoint(path string) (*updateCheckpoint, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	line, err := reader.ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	checkpoint := &updateCheckpoint{path: path}
+	if err = json.Unmarshal(line, checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: path=%s, %+v", path, err)
+	}
+	for {
+		line, err = reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var bz []byte
+		if err = json.Unmarshal(line, &bz); err != nil {
+			return nil, fmt.Errorf("invalid checkpoint header: path=%s, index=%d, %+v", path, len(checkpoint.Headers), err)
+		}
+		checkpoint.Headers = append(checkpoint.Headers, bz)
+	}
+	checkpoint.saved = len(line) == 0
+	return checkpoint, nil
+}
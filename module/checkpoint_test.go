@@ -0,0 +1,157 @@
+package module
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"os"
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"github.com/stretchr/testify/suite"
+)
+
+type checkpointChain struct {
+	Chain
+}
+
+func (c *checkpointChain) ChainID() string {
+	return "9999"
+}
+
+type CheckpointTestSuite struct {
+	suite.Suite
+	prover *Prover
+}
+
+func TestCheckpointTestSuite(t *testing.T) {
+	suite.Run(t, new(CheckpointTestSuite))
+}
+
+func (ts *CheckpointTestSuite) SetupTest() {
+	err := log.InitLogger("DEBUG", "text", "stdout", false)
+	ts.Require().NoError(err)
+	ts.prover = NewProver(&checkpointChain{}, &ProverConfig{Network: string(Localnet)}).(*Prover)
+	ts.Require().NoError(ts.prover.Init(ts.T().TempDir(), 0, nil, false))
+}
+
+func (ts *CheckpointTestSuite) header(height uint64) *Header {
+	ethHeader, err := newETHHeader(&types.Header{Number: big.NewInt(int64(height)), Difficulty: big.NewInt(2)})
+	ts.Require().NoError(err)
+	return &Header{Headers: []*ETHHeader{ethHeader}}
+}
+
+func (ts *CheckpointTestSuite) heights(headers []*Header) []uint64 {
+	var heights []uint64
+	for _, h := range headers {
+		heights = append(heights, h.GetHeight().GetRevisionHeight())
+	}
+	return heights
+}
+
+func (ts *CheckpointTestSuite) TestResume() {
+	ctx := context.Background()
+	target := ts.header(3500)
+
+	// Nothing to resume at first
+	checkpoint, restored := ts.prover.resumeCheckpoint(ctx, "client-0", clienttypes.NewHeight(0, 100), target)
+	ts.Require().Empty(restored)
+	for _, height := range []uint64{1000, 2000, 3000, 3500} {
+		ts.Require().NoError(checkpoint.append(ts.header(height)))
+	}
+	_, err := os.Stat(ts.prover.checkpointPath("client-0"))
+	ts.Require().NoError(err)
+
+	// Resume from the trusted height of the checkpoint
+	_, restored = ts.prover.resumeCheckpoint(ctx, "client-0", clienttypes.NewHeight(0, 100), target)
+	ts.Require().Equal([]uint64{1000, 2000, 3000, 3500}, ts.heights(restored))
+	ts.Require().Equal(uint64(100), restored[0].TrustedHeight.GetRevisionHeight())
+	for i := 1; i < len(restored); i++ {
+		ts.Require().Equal(restored[i-1].GetHeight(), *restored[i].TrustedHeight)
+	}
+
+	// Resume from the header stored by the counterparty, up to the target height
+	checkpoint, restored = ts.prover.resumeCheckpoint(ctx, "client-0", clienttypes.NewHeight(0, 1000), ts.header(3000))
+	ts.Require().Equal([]uint64{2000, 3000}, ts.heights(restored))
+	ts.Require().Equal(uint64(1000), restored[0].TrustedHeight.GetRevisionHeight())
+	ts.Require().Equal(uint64(1000), checkpoint.TrustedHeight)
+	ts.Require().NoError(checkpoint.append(ts.header(4000)))
+
+	// The rebased checkpoint is persisted with the appended header
+	_, restored = ts.prover.resumeCheckpoint(ctx, "client-0", clienttypes.NewHeight(0, 2000), ts.header(5000))
+	ts.Require().Equal([]uint64{3000, 4000}, ts.heights(restored))
+
+	// No header to resume from the last one
+	_, restored = ts.prover.resumeCheckpoint(ctx, "client-0", clienttypes.NewHeight(0, 4000), ts.header(5000))
+	ts.Require().Empty(restored)
+}
+
+func (ts *CheckpointTestSuite) TestResumeRevision() {
+	ctx := context.Background()
+	target := ts.header(3000)
+	checkpoint, _ := ts.prover.resumeCheckpoint(ctx, "client-0", clienttypes.NewHeight(1, 100), target)
+	ts.Require().NoError(checkpoint.append(ts.header(1000)))
+	ts.Require().NoError(checkpoint.append(ts.header(2000)))
+
+	// The trusted heights are chained in the revision of the client state
+	_, restored := ts.prover.resumeCheckpoint(ctx, "client-0", clienttypes.NewHeight(1, 100), target)
+	ts.Require().Len(restored, 2)
+	ts.Require().Equal(clienttypes.NewHeight(1, 100), *restored[0].TrustedHeight)
+	ts.Require().Equal(clienttypes.NewHeight(1, 1000), *restored[1].TrustedHeight)
+	ts.Require().Equal(clienttypes.NewHeight(1, 2000), restored[1].GetHeight())
+}
+
+func (ts *CheckpointTestSuite) TestAppend() {
+	ctx := context.Background()
+	path := ts.prover.checkpointPath("client-0")
+	checkpoint, _ := ts.prover.resumeCheckpoint(ctx, "client-0", clienttypes.NewHeight(0, 100), ts.header(3000))
+	ts.Require().NoError(checkpoint.append(ts.header(1000)))
+
+	// The following headers are appended without rewriting the previous ones
+	before, err := os.ReadFile(path)
+	ts.Require().NoError(err)
+	ts.Require().NoError(checkpoint.append(ts.header(2000)))
+	after, err := os.ReadFile(path)
+	ts.Require().NoError(err)
+	ts.Require().True(bytes.HasPrefix(after, before))
+	ts.Require().Equal(3, bytes.Count(after, []byte("\n")))
+
+	// The header broken by a restart while appending is dropped and the checkpoint is rewritten at the next append
+	ts.Require().NoError(os.WriteFile(path, append(after, []byte(`"AAAA`)...), 0644))
+	checkpoint, restored := ts.prover.resumeCheckpoint(ctx, "client-0", clienttypes.NewHeight(0, 1000), ts.header(3000))
+	ts.Require().Equal([]uint64{2000}, ts.heights(restored))
+	ts.Require().NoError(checkpoint.append(ts.header(3000)))
+	_, restored = ts.prover.resumeCheckpoint(ctx, "client-0", clienttypes.NewHeight(0, 1000), ts.header(3000))
+	ts.Require().Equal([]uint64{2000, 3000}, ts.heights(restored))
+}
+
+func (ts *CheckpointTestSuite) TestMismatch() {
+	ctx := context.Background()
+	target := ts.header(3000)
+	checkpoint, _ := ts.prover.resumeCheckpoint(ctx, "client-0", clienttypes.NewHeight(0, 100), target)
+	ts.Require().NoError(checkpoint.append(ts.header(1000)))
+	ts.Require().NoError(checkpoint.append(ts.header(2000)))
+
+	// The client was updated from another height
+	checkpoint, restored := ts.prover.resumeCheckpoint(ctx, "client-0", clienttypes.NewHeight(0, 1500), target)
+	ts.Require().Empty(restored)
+	ts.Require().Equal(uint64(1500), checkpoint.TrustedHeight)
+	ts.Require().Empty(checkpoint.Headers)
+
+	// Another client
+	_, restored = ts.prover.resumeCheckpoint(ctx, "client-1", clienttypes.NewHeight(0, 100), target)
+	ts.Require().Empty(restored)
+
+	// Broken checkpoint
+	ts.Require().NoError(os.WriteFile(ts.prover.checkpointPath("client-0"), []byte("{"), 0644))
+	_, restored = ts.prover.resumeCheckpoint(ctx, "client-0", clienttypes.NewHeight(0, 100), target)
+	ts.Require().Empty(restored)
+
+	// Disabled without the home path
+	ts.prover.homePath = ""
+	checkpoint, _ = ts.prover.resumeCheckpoint(ctx, "client-0", clienttypes.NewHeight(0, 100), target)
+	ts.Require().NoError(checkpoint.append(ts.header(1000)))
+	ts.Require().Len(checkpoint.Headers, 1)
+}
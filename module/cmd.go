@@ -0,0 +1,312 @@
+package module
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gogoproto/proto"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hyperledger-labs/yui-relayer/config"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/hyperledger-labs/yui-relayer/coreutil"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagHeight    = "height"
+	flagOutput    = "output"
+	flagSubmit    = "submit"
+	flagAuthority = "authority"
+	flagRPCAddr   = "rpc-addr"
+	flagInterval  = "interval"
+
+	flagTrustedHeight  = "trusted-height"
+	flagNetwork        = "network"
+	flagRevisionNumber = "revision-number"
+	flagFormat         = "format"
+	flagLimitHeight    = "limit-height"
+)
+
+func parliaCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "parlia",
+		Short: "Parlia specific commands",
+	}
+	cmd.AddCommand(recoveryCmd(ctx))
+	cmd.AddCommand(watchtowerCmd(ctx))
+	cmd.AddCommand(misbehaviourCmd(ctx))
+	cmd.AddCommand(latestHeaderCmd(ctx))
+	cmd.AddCommand(validatorsCmd(ctx))
+	cmd.AddCommand(forksCmd(ctx))
+	cmd.AddCommand(decodeCmd(ctx))
+	cmd.AddCommand(dryRunCmd(ctx))
+	cmd.AddCommand(healthCmd(ctx))
+	cmd.AddCommand(finalityCmd(ctx))
+	return cmd
+}
+
+func recoveryCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recovery",
+		Short: "Commands to recover an expired or frozen Parlia client",
+	}
+	cmd.AddCommand(substituteClientCmd(ctx))
+	cmd.AddCommand(recoverClientMsgCmd())
+	return cmd
+}
+
+func substituteClientCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "substitute [path-name] [chain-id]",
+		Short: "Create MsgCreateClient of a substitute client for the Parlia client of the chain on the counterparty",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			self, counterparty, err := chainsFromPath(ctx, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			cpHeight, err := counterparty.LatestHeight(cmd.Context())
+			if err != nil {
+				return err
+			}
+			res, err := counterparty.QueryClientState(core.NewQueryContext(cmd.Context(), cpHeight))
+			if err != nil {
+				return fmt.Errorf("failed to query the subject client state: client=%s, %+v", counterparty.Path().ClientID, err)
+			}
+			var subject exported.ClientState
+			if err = ctx.Codec.UnpackAny(res.ClientState, &subject); err != nil {
+				return err
+			}
+
+			var height exported.Height
+			if h, _ := cmd.Flags().GetUint64(flagHeight); h > 0 {
+				height = clienttypes.NewHeight(subject.GetLatestHeight().GetRevisionNumber(), h)
+			}
+			cs, consState, err := CreateSubstituteLightClientState(cmd.Context(), self, subject, height)
+			if err != nil {
+				return err
+			}
+			signer, err := counterparty.GetAddress()
+			if err != nil {
+				return err
+			}
+			msg, err := clienttypes.NewMsgCreateClient(cs, consState, signer.String())
+			if err != nil {
+				return err
+			}
+			if submit, _ := cmd.Flags().GetBool(flagSubmit); submit {
+				if _, err = counterparty.SendMsgs(cmd.Context(), []sdk.Msg{msg}); err != nil {
+					return err
+				}
+			}
+			output, _ := cmd.Flags().GetString(flagOutput)
+			return printProtoJSON(cmd, ctx.Codec, msg, output)
+		},
+	}
+	cmd.Flags().Uint64(flagHeight, 0, "finalized height of the substitute client. the latest finalized height is used if 0")
+	cmd.Flags().String(flagOutput, "", "file to write the message to. stdout is used if empty")
+	cmd.Flags().Bool(flagSubmit, false, "submit the message to the counterparty")
+	return cmd
+}
+
+func recoverClientMsgCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recover-msg [subject-client-id] [substitute-client-id]",
+		Short: "Create MsgRecoverClient to be submitted as a governance proposal",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			authority, _ := cmd.Flags().GetString(flagAuthority)
+			msg := clienttypes.NewMsgRecoverClient(authority, args[0], args[1])
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			output, _ := cmd.Flags().GetString(flagOutput)
+			return printProtoJSON(cmd, core.MakeCodec(), msg, output)
+		},
+	}
+	cmd.Flags().String(flagAuthority, "", "address of the authority allowed to recover clients (e.g. gov module account)")
+	cmd.Flags().String(flagOutput, "", "file to write the message to. stdout is used if empty")
+	_ = cmd.MarkFlagRequired(flagAuthority)
+	return cmd
+}
+
+func watchtowerCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watchtower [path-name] [chain-id]",
+		Short: "Monitor the finalized headers of the chain through multiple RPC endpoints and submit misbehaviour to the counterparty",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			self, counterparty, err := chainsFromPath(ctx, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			prover, err := coreutil.UnwrapProver[*Prover](self.Prover)
+			if err != nil {
+				return err
+			}
+			rpcAddrs, _ := cmd.Flags().GetStringSlice(flagRPCAddr)
+			watchtower, err := NewWatchtower(prover, counterparty, rpcAddrs)
+			if err != nil {
+				return err
+			}
+			interval, _ := cmd.Flags().GetDuration(flagInterval)
+			return watchtower.Run(cmd.Context(), interval)
+		},
+	}
+	cmd.Flags().StringSlice(flagRPCAddr, nil, "rpc address of an independent endpoint of the chain. can be specified multiple times")
+	cmd.Flags().Duration(flagInterval, 10*time.Second, "interval to check misbehaviour")
+	_ = cmd.MarkFlagRequired(flagRPCAddr)
+	return cmd
+}
+
+func misbehaviourCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "misbehaviour",
+		Short: "Commands to build misbehaviour evidence without the relayer config",
+	}
+	cmd.AddCommand(buildMisbehaviourCmd(ctx))
+	cmd.AddCommand(exportHeaderCmd(ctx))
+	return cmd
+}
+
+func buildMisbehaviourCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build [client-id] [source1] [source2]",
+		Short: "Build Misbehaviour from two conflicting headers. Each source is an RPC address or a file exported by export-header",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var headers []*Header
+			for _, source := range args[1:] {
+				header, err := misbehaviourHeaderFromSource(cmd, ctx.Codec, source)
+				if err != nil {
+					return fmt.Errorf("failed to get header from %s: %+v", source, err)
+				}
+				headers = append(headers, header)
+			}
+			misbehaviour, err := BuildMisbehaviour(args[0], headers[0], headers[1])
+			if err != nil {
+				return err
+			}
+			anyMisbehaviour, err := clienttypes.PackClientMessage(misbehaviour)
+			if err != nil {
+				return err
+			}
+			output, _ := cmd.Flags().GetString(flagOutput)
+			format, _ := cmd.Flags().GetString(flagFormat)
+			switch format {
+			case "json":
+				return printProtoJSON(cmd, ctx.Codec, anyMisbehaviour, output)
+			case "hex":
+				bz, err := anyMisbehaviour.Marshal()
+				if err != nil {
+					return err
+				}
+				return printString(cmd, common.Bytes2Hex(bz), output)
+			}
+			return fmt.Errorf("unknown format: %s", format)
+		},
+	}
+	addMisbehaviourHeaderFlags(cmd)
+	cmd.Flags().String(flagFormat, "json", "output format of the packed Any. json or hex")
+	cmd.Flags().String(flagOutput, "", "file to write the misbehaviour to. stdout is used if empty")
+	return cmd
+}
+
+func exportHeaderCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-header [rpc-addr]",
+		Short: "Export the verifiable header finalized at the height to be used as a source of build",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			header, err := misbehaviourHeaderFromSource(cmd, ctx.Codec, args[0])
+			if err != nil {
+				return err
+			}
+			output, _ := cmd.Flags().GetString(flagOutput)
+			return printProtoJSON(cmd, ctx.Codec, header, output)
+		},
+	}
+	addMisbehaviourHeaderFlags(cmd)
+	cmd.Flags().String(flagOutput, "", "file to write the header to. stdout is used if empty")
+	return cmd
+}
+
+func addMisbehaviourHeaderFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64(flagHeight, 0, "height of the conflicting headers. required for rpc sources")
+	cmd.Flags().Uint64(flagTrustedHeight, 0, "height of the consensus state trusted by the client. required for rpc sources")
+	cmd.Flags().Uint64(flagRevisionNumber, 0, "revision number of the heights")
+	cmd.Flags().String(flagNetwork, string(Mainnet), "network of the chain to select the fork specs")
+}
+
+// misbehaviourHeaderFromSource queries the header from the source if it is an RPC address, otherwise reads it from the file.
+func misbehaviourHeaderFromSource(cmd *cobra.Command, cdc codec.JSONCodec, source string) (*Header, error) {
+	if !isRPCAddr(source) {
+		bz, err := os.ReadFile(source)
+		if err != nil {
+			return nil, err
+		}
+		var header Header
+		if err = cdc.UnmarshalJSON(bz, &header); err != nil {
+			return nil, err
+		}
+		return &header, nil
+	}
+	height, _ := cmd.Flags().GetUint64(flagHeight)
+	trustedHeight, _ := cmd.Flags().GetUint64(flagTrustedHeight)
+	if height == 0 || trustedHeight == 0 {
+		return nil, fmt.Errorf("--%s and --%s are required for rpc sources", flagHeight, flagTrustedHeight)
+	}
+	network, _ := cmd.Flags().GetString(flagNetwork)
+	revisionNumber, _ := cmd.Flags().GetUint64(flagRevisionNumber)
+	proverConfig := &ProverConfig{Network: network, RevisionNumber: revisionNumber}
+	if err := proverConfig.Validate(); err != nil {
+		return nil, err
+	}
+	return QueryMisbehaviourHeader(cmd.Context(), source, proverConfig, height, trustedHeight)
+}
+
+func isRPCAddr(source string) bool {
+	for _, scheme := range []string{"http://", "https://", "ws://", "wss://"} {
+		if strings.HasPrefix(source, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// chainsFromPath returns the chain of `chainID` and its counterparty in the path.
+func chainsFromPath(ctx *config.Context, pathName string, chainID string) (*core.ProvableChain, *core.ProvableChain, error) {
+	chains, src, dst, err := ctx.Config.ChainsFromPath(pathName)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch chainID {
+	case src:
+		return chains[src], chains[dst], nil
+	case dst:
+		return chains[dst], chains[src], nil
+	}
+	return nil, nil, fmt.Errorf("chain %s is not in path %s", chainID, pathName)
+}
+
+func printProtoJSON(cmd *cobra.Command, cdc codec.JSONCodec, msg proto.Message, output string) error {
+	bz, err := cdc.MarshalJSON(msg)
+	if err != nil {
+		return err
+	}
+	return printString(cmd, string(bz), output)
+}
+
+func printString(cmd *cobra.Command, value string, output string) error {
+	if output != "" {
+		return os.WriteFile(output, []byte(value), 0644)
+	}
+	_, err := fmt.Fprintln(cmd.OutOrStdout(), value)
+	return err
+}
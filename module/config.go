@@ -21,8 +21,24 @@ func (c *ProverConfig) Build(chain core.Chain) (core.Prover, error) {
 }
 
 func (c *ProverConfig) Validate() error {
-	if GetForkParameters(Network(c.Network)) == nil {
+	forkSpecs := GetForkParameters(Network(c.Network))
+	if forkSpecs == nil {
 		return fmt.Errorf("unknown network: %s", c.Network)
 	}
+	// The epoch length of the trusted height is checked again when the headers are set up.
+	if _, err := resolveSubmissionInterval(c.SubmissionInterval, forkSpecs[len(forkSpecs)-1].EpochLength); err != nil {
+		return err
+	}
+	recovery := c.GetFastFinalityRecovery()
+	switch recovery.GetStrategy() {
+	case FastFinalityRecovery_STRATEGY_WIDEN_WINDOW:
+		if recovery.GetWindowExtension() == 0 {
+			return fmt.Errorf("window_extension is required for %s", recovery.GetStrategy())
+		}
+	case FastFinalityRecovery_STRATEGY_RETRY:
+		if recovery.GetMaxRetries() == 0 || recovery.GetRetryInterval() <= 0 {
+			return fmt.Errorf("max_retries and retry_interval are required for %s", recovery.GetStrategy())
+		}
+	}
 	return nil
 }
@@ -27,6 +27,46 @@ var _ = time.Kitchen
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+type FastFinalityRecovery_Strategy int32
+
+const (
+	// Same as STRATEGY_FAIL.
+	FastFinalityRecovery_STRATEGY_UNSPECIFIED FastFinalityRecovery_Strategy = 0
+	// Fail with ErrInsufficientVoteAttestation.
+	FastFinalityRecovery_STRATEGY_FAIL FastFinalityRecovery_Strategy = 1
+	// Search the descendants proving the finality beyond the window by window_extension blocks.
+	// The finalized header is searched among the neighbouring heights within the same epoch.
+	FastFinalityRecovery_STRATEGY_WIDEN_WINDOW FastFinalityRecovery_Strategy = 2
+	// Wait for retry_interval and search again up to max_retries times as the chain proceeds.
+	FastFinalityRecovery_STRATEGY_RETRY FastFinalityRecovery_Strategy = 3
+	// Submit the headers found so far. The client may be stuck until the finalized header is found.
+	FastFinalityRecovery_STRATEGY_PARTIAL FastFinalityRecovery_Strategy = 4
+)
+
+var FastFinalityRecovery_Strategy_name = map[int32]string{
+	0: "STRATEGY_UNSPECIFIED",
+	1: "STRATEGY_FAIL",
+	2: "STRATEGY_WIDEN_WINDOW",
+	3: "STRATEGY_RETRY",
+	4: "STRATEGY_PARTIAL",
+}
+
+var FastFinalityRecovery_Strategy_value = map[string]int32{
+	"STRATEGY_UNSPECIFIED":  0,
+	"STRATEGY_FAIL":         1,
+	"STRATEGY_WIDEN_WINDOW": 2,
+	"STRATEGY_RETRY":        3,
+	"STRATEGY_PARTIAL":      4,
+}
+
+func (x FastFinalityRecovery_Strategy) String() string {
+	return proto.EnumName(FastFinalityRecovery_Strategy_name, int32(x))
+}
+
+func (FastFinalityRecovery_Strategy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_4d00ceb9ab8b08a6, []int{2, 0}
+}
+
 type ProverConfig struct {
 	TrustingPeriod time.Duration `protobuf:"bytes,1,opt,name=trusting_period,json=trustingPeriod,proto3,stdduration" json:"trusting_period"`
 	MaxClockDrift  time.Duration `protobuf:"bytes,2,opt,name=max_clock_drift,json=maxClockDrift,proto3,stdduration" json:"max_clock_drift"`
@@ -38,6 +78,29 @@ type ProverConfig struct {
 	RefreshBlockDifferenceThreshold uint64 `protobuf:"varint,4,opt,name=refresh_block_difference_threshold,json=refreshBlockDifferenceThreshold,proto3" json:"refresh_block_difference_threshold,omitempty"`
 	// Network name
 	Network string `protobuf:"bytes,5,opt,name=network,proto3" json:"network,omitempty"`
+	// Revision number of the self chain used for the heights in ClientState, Header and proofs.
+	// It must be incremented when the chain ID changes by an upgrade.
+	RevisionNumber uint64 `protobuf:"varint,6,opt,name=revision_number,json=revisionNumber,proto3" json:"revision_number,omitempty"`
+	// Recovery strategy when no finalized header is found for an intermediate header.
+	// If it is not set, the update fails with ErrInsufficientVoteAttestation.
+	FastFinalityRecovery *FastFinalityRecovery `protobuf:"bytes,7,opt,name=fast_finality_recovery,json=fastFinalityRecovery,proto3" json:"fast_finality_recovery,omitempty"`
+	// Interval in blocks of the intermediate headers and the window to search their finality.
+	// It must divide the epoch length so that no epoch is skipped.
+	// If the value is 0, the epoch length of the fork spec at the trusted height is used.
+	SubmissionInterval uint64 `protobuf:"varint,8,opt,name=submission_interval,json=submissionInterval,proto3" json:"submission_interval,omitempty"`
+	// Budget of the headers submitted to the counterparty in one update.
+	UpdateBudget *UpdateBudget `protobuf:"bytes,9,opt,name=update_budget,json=updateBudget,proto3" json:"update_budget,omitempty"`
+	// Maximum number of the intermediate headers queried concurrently.
+	// If the value is 0, the headers are queried one after another.
+	HeaderQueryConcurrency uint32 `protobuf:"varint,10,opt,name=header_query_concurrency,json=headerQueryConcurrency,proto3" json:"header_query_concurrency,omitempty"`
+	// Maximum number of blocks searched backward from the latest block for the latest finalized header.
+	// If the value is 0, the epoch length of the latest fork spec is used. The search was unbounded before this option was added.
+	MaxFinalitySearchDepth uint64 `protobuf:"varint,11,opt,name=max_finality_search_depth,json=maxFinalitySearchDepth,proto3" json:"max_finality_search_depth,omitempty"`
+	// Query the `finalized` block tag of the node to skip the backward search for the latest finalized header
+	// and cross-check it with the height derived from the vote attestations.
+	UseFinalizedTag bool `protobuf:"varint,12,opt,name=use_finalized_tag,json=useFinalizedTag,proto3" json:"use_finalized_tag,omitempty"`
+	// Difference in blocks between the finalized height reported by the node and the derived one to alert.
+	FinalizedTagTolerance uint64 `protobuf:"varint,13,opt,name=finalized_tag_tolerance,json=finalizedTagTolerance,proto3" json:"finalized_tag_tolerance,omitempty"`
 }
 
 func (m *ProverConfig) Reset()         { *m = ProverConfig{} }
@@ -108,6 +171,201 @@ func (m *ProverConfig) GetNetwork() string {
 	return ""
 }
 
+func (m *ProverConfig) GetRevisionNumber() uint64 {
+	if m != nil {
+		return m.RevisionNumber
+	}
+	return 0
+}
+
+func (m *ProverConfig) GetFastFinalityRecovery() *FastFinalityRecovery {
+	if m != nil {
+		return m.FastFinalityRecovery
+	}
+	return nil
+}
+
+func (m *ProverConfig) GetSubmissionInterval() uint64 {
+	if m != nil {
+		return m.SubmissionInterval
+	}
+	return 0
+}
+
+func (m *ProverConfig) GetUpdateBudget() *UpdateBudget {
+	if m != nil {
+		return m.UpdateBudget
+	}
+	return nil
+}
+
+func (m *ProverConfig) GetHeaderQueryConcurrency() uint32 {
+	if m != nil {
+		return m.HeaderQueryConcurrency
+	}
+	return 0
+}
+
+func (m *ProverConfig) GetMaxFinalitySearchDepth() uint64 {
+	if m != nil {
+		return m.MaxFinalitySearchDepth
+	}
+	return 0
+}
+
+func (m *ProverConfig) GetUseFinalizedTag() bool {
+	if m != nil {
+		return m.UseFinalizedTag
+	}
+	return false
+}
+
+func (m *ProverConfig) GetFinalizedTagTolerance() uint64 {
+	if m != nil {
+		return m.FinalizedTagTolerance
+	}
+	return 0
+}
+
+type UpdateBudget struct {
+	// Maximum encoded size in bytes of the headers. If the value is 0, the size is not limited.
+	MaxBytes uint64 `protobuf:"varint,1,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	// Maximum estimated gas of the headers. If the value is 0, the gas is not limited.
+	// The gas of a header is estimated as gas_per_header + gas_per_byte * its encoded size.
+	MaxGas       uint64 `protobuf:"varint,2,opt,name=max_gas,json=maxGas,proto3" json:"max_gas,omitempty"`
+	GasPerHeader uint64 `protobuf:"varint,3,opt,name=gas_per_header,json=gasPerHeader,proto3" json:"gas_per_header,omitempty"`
+	GasPerByte   uint64 `protobuf:"varint,4,opt,name=gas_per_byte,json=gasPerByte,proto3" json:"gas_per_byte,omitempty"`
+}
+
+func (m *UpdateBudget) Reset()         { *m = UpdateBudget{} }
+func (m *UpdateBudget) String() string { return proto.CompactTextString(m) }
+func (*UpdateBudget) ProtoMessage()    {}
+func (*UpdateBudget) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4d00ceb9ab8b08a6, []int{1}
+}
+func (m *UpdateBudget) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UpdateBudget) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_UpdateBudget.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *UpdateBudget) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateBudget.Merge(m, src)
+}
+func (m *UpdateBudget) XXX_Size() int {
+	return m.Size()
+}
+func (m *UpdateBudget) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateBudget.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateBudget proto.InternalMessageInfo
+
+func (m *UpdateBudget) GetMaxBytes() uint64 {
+	if m != nil {
+		return m.MaxBytes
+	}
+	return 0
+}
+
+func (m *UpdateBudget) GetMaxGas() uint64 {
+	if m != nil {
+		return m.MaxGas
+	}
+	return 0
+}
+
+func (m *UpdateBudget) GetGasPerHeader() uint64 {
+	if m != nil {
+		return m.GasPerHeader
+	}
+	return 0
+}
+
+func (m *UpdateBudget) GetGasPerByte() uint64 {
+	if m != nil {
+		return m.GasPerByte
+	}
+	return 0
+}
+
+type FastFinalityRecovery struct {
+	Strategy        FastFinalityRecovery_Strategy `protobuf:"varint,1,opt,name=strategy,proto3,enum=relayer.provers.parlia.config.FastFinalityRecovery_Strategy" json:"strategy,omitempty"`
+	WindowExtension uint64                        `protobuf:"varint,2,opt,name=window_extension,json=windowExtension,proto3" json:"window_extension,omitempty"`
+	MaxRetries      uint32                        `protobuf:"varint,3,opt,name=max_retries,json=maxRetries,proto3" json:"max_retries,omitempty"`
+	RetryInterval   time.Duration                 `protobuf:"bytes,4,opt,name=retry_interval,json=retryInterval,proto3,stdduration" json:"retry_interval"`
+}
+
+func (m *FastFinalityRecovery) Reset()         { *m = FastFinalityRecovery{} }
+func (m *FastFinalityRecovery) String() string { return proto.CompactTextString(m) }
+func (*FastFinalityRecovery) ProtoMessage()    {}
+func (*FastFinalityRecovery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4d00ceb9ab8b08a6, []int{2}
+}
+func (m *FastFinalityRecovery) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FastFinalityRecovery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FastFinalityRecovery.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FastFinalityRecovery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FastFinalityRecovery.Merge(m, src)
+}
+func (m *FastFinalityRecovery) XXX_Size() int {
+	return m.Size()
+}
+func (m *FastFinalityRecovery) XXX_DiscardUnknown() {
+	xxx_messageInfo_FastFinalityRecovery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FastFinalityRecovery proto.InternalMessageInfo
+
+func (m *FastFinalityRecovery) GetStrategy() FastFinalityRecovery_Strategy {
+	if m != nil {
+		return m.Strategy
+	}
+	return FastFinalityRecovery_STRATEGY_UNSPECIFIED
+}
+
+func (m *FastFinalityRecovery) GetWindowExtension() uint64 {
+	if m != nil {
+		return m.WindowExtension
+	}
+	return 0
+}
+
+func (m *FastFinalityRecovery) GetMaxRetries() uint32 {
+	if m != nil {
+		return m.MaxRetries
+	}
+	return 0
+}
+
+func (m *FastFinalityRecovery) GetRetryInterval() time.Duration {
+	if m != nil {
+		return m.RetryInterval
+	}
+	return 0
+}
+
 type Fraction struct {
 	Numerator   uint64 `protobuf:"varint,1,opt,name=numerator,proto3" json:"numerator,omitempty"`
 	Denominator uint64 `protobuf:"varint,2,opt,name=denominator,proto3" json:"denominator,omitempty"`
@@ -117,7 +375,7 @@ func (m *Fraction) Reset()         { *m = Fraction{} }
 func (m *Fraction) String() string { return proto.CompactTextString(m) }
 func (*Fraction) ProtoMessage()    {}
 func (*Fraction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4d00ceb9ab8b08a6, []int{1}
+	return fileDescriptor_4d00ceb9ab8b08a6, []int{3}
 }
 func (m *Fraction) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -161,7 +419,10 @@ func (m *Fraction) GetDenominator() uint64 {
 }
 
 func init() {
+	proto.RegisterEnum("relayer.provers.parlia.config.FastFinalityRecovery_Strategy", FastFinalityRecovery_Strategy_name, FastFinalityRecovery_Strategy_value)
 	proto.RegisterType((*ProverConfig)(nil), "relayer.provers.parlia.config.ProverConfig")
+	proto.RegisterType((*UpdateBudget)(nil), "relayer.provers.parlia.config.UpdateBudget")
+	proto.RegisterType((*FastFinalityRecovery)(nil), "relayer.provers.parlia.config.FastFinalityRecovery")
 	proto.RegisterType((*Fraction)(nil), "relayer.provers.parlia.config.Fraction")
 }
 
@@ -170,33 +431,64 @@ func init() {
 }
 
 var fileDescriptor_4d00ceb9ab8b08a6 = []byte{
-	// 416 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x91, 0xd1, 0x6a, 0xd4, 0x40,
-	0x14, 0x86, 0x37, 0xeb, 0xaa, 0xed, 0x54, 0x2d, 0x0c, 0x45, 0x62, 0xd1, 0xec, 0xb2, 0x37, 0x2e,
-	0x42, 0x67, 0x40, 0xdf, 0x60, 0x5b, 0x04, 0xad, 0x17, 0x25, 0x78, 0x25, 0x48, 0x98, 0x4c, 0x4e,
-	0xb2, 0x43, 0x93, 0x39, 0xe1, 0xec, 0x44, 0xeb, 0x5b, 0x78, 0xe9, 0x23, 0xf5, 0xb2, 0x97, 0x5e,
-	0xa9, 0xec, 0x3e, 0x80, 0xaf, 0x20, 0x99, 0x24, 0x5d, 0xaf, 0xc4, 0xab, 0x99, 0x9c, 0xf9, 0xff,
-	0xef, 0x3f, 0xfc, 0x61, 0x2f, 0x08, 0x4a, 0xf5, 0x05, 0x48, 0xd6, 0x84, 0x9f, 0x80, 0xd6, 0xb2,
-	0x56, 0x54, 0x1a, 0x25, 0x35, 0xda, 0xdc, 0x14, 0xfd, 0x21, 0x6a, 0x42, 0x87, 0xfc, 0x59, 0xaf,
-	0x15, 0xbd, 0x56, 0x74, 0x5a, 0xd1, 0x89, 0x8e, 0xa3, 0x02, 0xb1, 0x28, 0x41, 0x7a, 0x71, 0xda,
-	0xe4, 0x32, 0x6b, 0x48, 0x39, 0x83, 0xb6, 0xb3, 0x1f, 0x1f, 0x15, 0x58, 0xa0, 0xbf, 0xca, 0xf6,
-	0xd6, 0x4d, 0xe7, 0xbf, 0xc7, 0xec, 0xc1, 0x85, 0xe7, 0x9d, 0x7a, 0x0c, 0x7f, 0xc7, 0x0e, 0x1d,
-	0x35, 0x6b, 0x67, 0x6c, 0x91, 0xd4, 0x40, 0x06, 0xb3, 0x30, 0x98, 0x05, 0x8b, 0x83, 0x97, 0x4f,
-	0x44, 0x17, 0x20, 0x86, 0x00, 0x71, 0xd6, 0x07, 0x2c, 0xf7, 0xae, 0x7f, 0x4c, 0x47, 0xdf, 0x7e,
-	0x4e, 0x83, 0xf8, 0xd1, 0xe0, 0xbd, 0xf0, 0x56, 0x7e, 0xce, 0x0e, 0x2b, 0x75, 0x95, 0xe8, 0x12,
-	0xf5, 0x65, 0x92, 0x91, 0xc9, 0x5d, 0x38, 0xfe, 0x7f, 0xda, 0xc3, 0x4a, 0x5d, 0x9d, 0xb6, 0xd6,
-	0xb3, 0xd6, 0xc9, 0x3f, 0xb2, 0xc7, 0x04, 0x39, 0xc1, 0x7a, 0x95, 0xb8, 0x55, 0x7b, 0x60, 0x99,
-	0x25, 0xa4, 0x1c, 0x84, 0x77, 0x3c, 0xf3, 0xb9, 0xf8, 0x67, 0x43, 0xe2, 0x35, 0x29, 0xdd, 0x26,
-	0xc4, 0x47, 0x3d, 0xe6, 0xfd, 0x40, 0x89, 0x95, 0x03, 0x7e, 0xce, 0xe6, 0x03, 0x3e, 0xed, 0xf6,
-	0x35, 0x79, 0x0e, 0x04, 0x56, 0xc3, 0x2e, 0x2f, 0x9c, 0xcc, 0x82, 0xc5, 0x24, 0x9e, 0xf6, 0xca,
-	0xa5, 0xdf, 0xee, 0x56, 0x77, 0x0b, 0xe4, 0x21, 0xbb, 0x6f, 0xc1, 0x7d, 0x46, 0xba, 0x0c, 0xef,
-	0xce, 0x82, 0xc5, 0x7e, 0x3c, 0x7c, 0xce, 0xdf, 0xb2, 0xbd, 0x61, 0x11, 0xfe, 0x94, 0xed, 0xdb,
-	0xa6, 0x02, 0x52, 0x0e, 0xc9, 0xd7, 0x3c, 0x89, 0x77, 0x03, 0x3e, 0x63, 0x07, 0x19, 0x58, 0xac,
-	0x8c, 0xf5, 0xef, 0x63, 0xff, 0xfe, 0xf7, 0x68, 0xf9, 0xe6, 0x7a, 0x13, 0x05, 0x37, 0x9b, 0x28,
-	0xf8, 0xb5, 0x89, 0x82, 0xaf, 0xdb, 0x68, 0x74, 0xb3, 0x8d, 0x46, 0xdf, 0xb7, 0xd1, 0xe8, 0x83,
-	0x2c, 0x8c, 0x5b, 0x35, 0xa9, 0xd0, 0x58, 0xc9, 0x4c, 0x39, 0xa5, 0x57, 0xca, 0xd8, 0x52, 0xa5,
-	0xd2, 0xa4, 0xfa, 0xa4, 0xab, 0xe5, 0xc4, 0xb7, 0x25, 0x2b, 0xcc, 0x9a, 0x12, 0xd2, 0x7b, 0xfe,
-	0x47, 0xbc, 0xfa, 0x13, 0x00, 0x00, 0xff, 0xff, 0xa3, 0xbf, 0xc9, 0x85, 0x92, 0x02, 0x00, 0x00,
+	// 898 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xdf, 0x6f, 0xdb, 0x44,
+	0x1c, 0xaf, 0xd7, 0xd0, 0xa6, 0xd7, 0x24, 0xcd, 0x8e, 0xac, 0x73, 0x07, 0xa4, 0x51, 0x84, 0xb4,
+	0x30, 0x34, 0x5b, 0xda, 0x24, 0x04, 0x12, 0x2f, 0x4d, 0x93, 0x8c, 0x6c, 0x55, 0x09, 0xd7, 0x4c,
+	0x65, 0x48, 0xc8, 0x3a, 0xdb, 0x5f, 0x3b, 0xa7, 0xd9, 0xbe, 0x70, 0x3e, 0xb7, 0x09, 0xe2, 0x99,
+	0x27, 0x1e, 0x78, 0xe4, 0x4f, 0xda, 0xe3, 0x1e, 0x79, 0x62, 0xa8, 0xfd, 0x47, 0xd0, 0x9d, 0xed,
+	0xa4, 0x48, 0x13, 0x03, 0x9e, 0xec, 0xfb, 0x7c, 0xbe, 0x9f, 0xcf, 0xf7, 0x87, 0xbf, 0x67, 0xf4,
+	0x40, 0x40, 0x44, 0x97, 0x20, 0xec, 0xb9, 0xe0, 0x17, 0x20, 0x52, 0x7b, 0x4e, 0x45, 0xc4, 0xa8,
+	0xed, 0xf1, 0x24, 0x60, 0x61, 0xf1, 0xb0, 0xe6, 0x82, 0x4b, 0x8e, 0x3f, 0x2a, 0x62, 0xad, 0x22,
+	0xd6, 0xca, 0x63, 0xad, 0x3c, 0xe8, 0x5e, 0x3b, 0xe4, 0x3c, 0x8c, 0xc0, 0xd6, 0xc1, 0x6e, 0x16,
+	0xd8, 0x7e, 0x26, 0xa8, 0x64, 0x3c, 0xc9, 0xe5, 0xf7, 0x5a, 0x21, 0x0f, 0xb9, 0x7e, 0xb5, 0xd5,
+	0x5b, 0x8e, 0x76, 0xdf, 0x6c, 0xa1, 0xda, 0x44, 0xfb, 0x1d, 0x6b, 0x1b, 0x7c, 0x82, 0xf6, 0xa4,
+	0xc8, 0x52, 0xc9, 0x92, 0xd0, 0x99, 0x83, 0x60, 0xdc, 0x37, 0x8d, 0x8e, 0xd1, 0xdb, 0x7d, 0x74,
+	0x60, 0xe5, 0x09, 0xac, 0x32, 0x81, 0x35, 0x28, 0x12, 0xf4, 0xab, 0xaf, 0xfe, 0x38, 0xdc, 0xf8,
+	0xed, 0xcd, 0xa1, 0x41, 0x1a, 0xa5, 0x76, 0xa2, 0xa5, 0xf8, 0x19, 0xda, 0x8b, 0xe9, 0xc2, 0xf1,
+	0x22, 0xee, 0xbd, 0x74, 0x7c, 0xc1, 0x02, 0x69, 0xde, 0xfa, 0xf7, 0x6e, 0xf5, 0x98, 0x2e, 0x8e,
+	0x95, 0x74, 0xa0, 0x94, 0xf8, 0x7b, 0xb4, 0x2f, 0x20, 0x10, 0x90, 0xce, 0x1c, 0x39, 0x53, 0x0f,
+	0x1e, 0xf9, 0x8e, 0xa0, 0x12, 0xcc, 0x4d, 0xed, 0x79, 0xdf, 0xfa, 0xc7, 0x09, 0x59, 0x23, 0x41,
+	0x3d, 0x95, 0x81, 0xb4, 0x0a, 0x9b, 0x69, 0xe9, 0x42, 0xa8, 0x04, 0xfc, 0x0c, 0x75, 0x4b, 0x7b,
+	0x37, 0xaf, 0x97, 0x05, 0x01, 0x08, 0x48, 0x3c, 0x58, 0xe7, 0x33, 0x2b, 0x1d, 0xa3, 0x57, 0x21,
+	0x87, 0x45, 0x64, 0x5f, 0x57, 0xb7, 0x8a, 0x5b, 0x19, 0x62, 0x13, 0x6d, 0x27, 0x20, 0x2f, 0xb9,
+	0x78, 0x69, 0xbe, 0xd7, 0x31, 0x7a, 0x3b, 0xa4, 0x3c, 0xe2, 0xfb, 0x68, 0x4f, 0xc0, 0x05, 0x4b,
+	0x19, 0x4f, 0x9c, 0x24, 0x8b, 0x5d, 0x10, 0xe6, 0x96, 0xf6, 0x6c, 0x94, 0xf0, 0xa9, 0x46, 0x31,
+	0x43, 0xfb, 0x01, 0x4d, 0xa5, 0x13, 0xb0, 0x84, 0x46, 0x4c, 0x2e, 0x1d, 0x01, 0x9e, 0x6a, 0x6b,
+	0x69, 0x6e, 0xeb, 0x76, 0x1f, 0xbf, 0xab, 0x5d, 0x9a, 0xca, 0x51, 0xa1, 0x25, 0x85, 0x94, 0xb4,
+	0x82, 0xb7, 0xa0, 0xd8, 0x46, 0xef, 0xa7, 0x99, 0x1b, 0xb3, 0x54, 0x57, 0xc5, 0x12, 0x09, 0xe2,
+	0x82, 0x46, 0x66, 0x55, 0xd7, 0x85, 0xd7, 0xd4, 0xb8, 0x60, 0xf0, 0x04, 0xd5, 0xb3, 0xb9, 0x4f,
+	0x25, 0x38, 0x6e, 0xe6, 0x87, 0x20, 0xcd, 0x1d, 0x5d, 0xd2, 0xa7, 0xef, 0x28, 0xe9, 0xb9, 0xd6,
+	0xf4, 0xb5, 0x84, 0xd4, 0xb2, 0x1b, 0x27, 0xfc, 0x39, 0x32, 0x67, 0x40, 0x7d, 0x10, 0xce, 0x0f,
+	0x19, 0x88, 0xa5, 0xe3, 0xf1, 0xc4, 0xcb, 0x84, 0x9a, 0xea, 0xd2, 0x44, 0x1d, 0xa3, 0x57, 0x27,
+	0xfb, 0x39, 0xff, 0x8d, 0xa2, 0x8f, 0xd7, 0x2c, 0xfe, 0x02, 0x1d, 0xa8, 0x1d, 0x5b, 0x8d, 0x29,
+	0x05, 0x2a, 0xbc, 0x99, 0xe3, 0xc3, 0x5c, 0xce, 0xcc, 0x5d, 0xdd, 0xc2, 0x7e, 0x4c, 0x17, 0x65,
+	0xd3, 0x67, 0x9a, 0x1e, 0x28, 0x16, 0x3f, 0x40, 0xb7, 0xb3, 0x14, 0x0a, 0xe9, 0x8f, 0xe0, 0x3b,
+	0x92, 0x86, 0x66, 0xad, 0x63, 0xf4, 0xaa, 0x64, 0x2f, 0x4b, 0x61, 0x54, 0xe2, 0x53, 0x1a, 0xe2,
+	0xcf, 0xd0, 0xdd, 0xbf, 0xc5, 0x39, 0x92, 0x47, 0x20, 0x68, 0xe2, 0x81, 0x59, 0xd7, 0x49, 0xee,
+	0x04, 0x37, 0xc2, 0xa7, 0x25, 0xd9, 0xfd, 0xc5, 0x40, 0xb5, 0x9b, 0x7d, 0xe3, 0x0f, 0xd0, 0x8e,
+	0xaa, 0xd7, 0x5d, 0x4a, 0x48, 0xf5, 0xdd, 0xaa, 0x90, 0x6a, 0x4c, 0x17, 0x7d, 0x75, 0xc6, 0x77,
+	0xd1, 0xb6, 0x22, 0x43, 0x9a, 0xea, 0x8b, 0x52, 0x21, 0x5b, 0x31, 0x5d, 0x3c, 0xa1, 0x29, 0xfe,
+	0x18, 0x35, 0x42, 0x9a, 0xaa, 0x2b, 0xe9, 0xe4, 0x73, 0xd0, 0x4b, 0x5f, 0x21, 0xb5, 0x90, 0xa6,
+	0x13, 0x10, 0x5f, 0x69, 0x0c, 0x77, 0x50, 0xad, 0x8c, 0x52, 0xfe, 0xc5, 0xb6, 0xa2, 0x3c, 0x46,
+	0x65, 0xe8, 0xfe, 0xbc, 0x89, 0x5a, 0x6f, 0xdb, 0x0c, 0xfc, 0x2d, 0xaa, 0xa6, 0x52, 0xdd, 0xa6,
+	0x70, 0xa9, 0xab, 0x6a, 0x3c, 0xfa, 0xf2, 0x7f, 0x2c, 0x98, 0x75, 0x56, 0x78, 0x90, 0x95, 0x1b,
+	0xfe, 0x04, 0x35, 0x2f, 0x59, 0xe2, 0xf3, 0x4b, 0x07, 0x16, 0x12, 0x12, 0xb5, 0x48, 0x45, 0x73,
+	0x7b, 0x39, 0x3e, 0x2c, 0x61, 0x7c, 0x88, 0x76, 0x55, 0xfb, 0x02, 0xa4, 0x60, 0x90, 0xea, 0x16,
+	0xeb, 0x04, 0xc5, 0x74, 0x41, 0x72, 0x04, 0x3f, 0x45, 0x0d, 0x45, 0x2e, 0xd7, 0x4b, 0x5a, 0xf9,
+	0x0f, 0xff, 0x13, 0x2d, 0x2d, 0x97, 0xb8, 0xfb, 0x13, 0xaa, 0x96, 0xd5, 0x62, 0x13, 0xb5, 0xce,
+	0xa6, 0xe4, 0x68, 0x3a, 0x7c, 0xf2, 0xc2, 0x79, 0x7e, 0x7a, 0x36, 0x19, 0x1e, 0x8f, 0x47, 0xe3,
+	0xe1, 0xa0, 0xb9, 0x81, 0x6f, 0xa3, 0xfa, 0x8a, 0x19, 0x1d, 0x8d, 0x4f, 0x9a, 0x06, 0x3e, 0x40,
+	0x77, 0x56, 0xd0, 0xf9, 0x78, 0x30, 0x3c, 0x75, 0xce, 0xc7, 0xa7, 0x83, 0xaf, 0xcf, 0x9b, 0xb7,
+	0x30, 0x46, 0x8d, 0x15, 0x45, 0x86, 0x53, 0xf2, 0xa2, 0xb9, 0x89, 0x5b, 0xa8, 0xb9, 0xc2, 0x26,
+	0x47, 0x64, 0x3a, 0x3e, 0x3a, 0x69, 0x56, 0xba, 0x4f, 0x51, 0xb5, 0xfc, 0x21, 0xe1, 0x0f, 0xd1,
+	0x4e, 0x92, 0xc5, 0x20, 0xa8, 0xe4, 0xa2, 0x58, 0x89, 0x35, 0x80, 0x3b, 0x68, 0xd7, 0x87, 0x84,
+	0xc7, 0x2c, 0xd1, 0x7c, 0x3e, 0xba, 0x9b, 0x50, 0x7f, 0xfc, 0xea, 0xaa, 0x6d, 0xbc, 0xbe, 0x6a,
+	0x1b, 0x7f, 0x5e, 0xb5, 0x8d, 0x5f, 0xaf, 0xdb, 0x1b, 0xaf, 0xaf, 0xdb, 0x1b, 0xbf, 0x5f, 0xb7,
+	0x37, 0xbe, 0xb3, 0x43, 0x26, 0x67, 0x99, 0x6b, 0x79, 0x3c, 0xb6, 0x7d, 0x2a, 0xa9, 0x37, 0xa3,
+	0x2c, 0x89, 0xa8, 0x6b, 0x33, 0xd7, 0x7b, 0x98, 0x7f, 0xce, 0x87, 0xfa, 0x2b, 0xdb, 0x31, 0xf7,
+	0xb3, 0x08, 0xdc, 0x2d, 0x3d, 0xc0, 0xc7, 0x7f, 0x0d, 0x00, 0x2c, 0xa1, 0x29, 0xe2, 0x9a, 0x06,
+	0x00, 0x00,
 }
 
 func (m *ProverConfig) Marshal() (dAtA []byte, err error) {
@@ -219,6 +511,65 @@ func (m *ProverConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.FinalizedTagTolerance != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.FinalizedTagTolerance))
+		i--
+		dAtA[i] = 0x68
+	}
+	if m.UseFinalizedTag {
+		i--
+		if m.UseFinalizedTag {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x60
+	}
+	if m.MaxFinalitySearchDepth != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxFinalitySearchDepth))
+		i--
+		dAtA[i] = 0x58
+	}
+	if m.HeaderQueryConcurrency != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.HeaderQueryConcurrency))
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.UpdateBudget != nil {
+		{
+			size, err := m.UpdateBudget.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintConfig(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.SubmissionInterval != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.SubmissionInterval))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.FastFinalityRecovery != nil {
+		{
+			size, err := m.FastFinalityRecovery.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintConfig(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.RevisionNumber != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.RevisionNumber))
+		i--
+		dAtA[i] = 0x30
+	}
 	if len(m.Network) > 0 {
 		i -= len(m.Network)
 		copy(dAtA[i:], m.Network)
@@ -243,25 +594,114 @@ func (m *ProverConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x1a
 	}
-	n2, err2 := github_com_cosmos_gogoproto_types.StdDurationMarshalTo(m.MaxClockDrift, dAtA[i-github_com_cosmos_gogoproto_types.SizeOfStdDuration(m.MaxClockDrift):])
-	if err2 != nil {
-		return 0, err2
+	n4, err4 := github_com_cosmos_gogoproto_types.StdDurationMarshalTo(m.MaxClockDrift, dAtA[i-github_com_cosmos_gogoproto_types.SizeOfStdDuration(m.MaxClockDrift):])
+	if err4 != nil {
+		return 0, err4
 	}
-	i -= n2
-	i = encodeVarintConfig(dAtA, i, uint64(n2))
+	i -= n4
+	i = encodeVarintConfig(dAtA, i, uint64(n4))
 	i--
 	dAtA[i] = 0x12
-	n3, err3 := github_com_cosmos_gogoproto_types.StdDurationMarshalTo(m.TrustingPeriod, dAtA[i-github_com_cosmos_gogoproto_types.SizeOfStdDuration(m.TrustingPeriod):])
-	if err3 != nil {
-		return 0, err3
+	n5, err5 := github_com_cosmos_gogoproto_types.StdDurationMarshalTo(m.TrustingPeriod, dAtA[i-github_com_cosmos_gogoproto_types.SizeOfStdDuration(m.TrustingPeriod):])
+	if err5 != nil {
+		return 0, err5
 	}
-	i -= n3
-	i = encodeVarintConfig(dAtA, i, uint64(n3))
+	i -= n5
+	i = encodeVarintConfig(dAtA, i, uint64(n5))
 	i--
 	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
 
+func (m *UpdateBudget) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpdateBudget) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UpdateBudget) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.GasPerByte != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.GasPerByte))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.GasPerHeader != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.GasPerHeader))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.MaxGas != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxGas))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.MaxBytes != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxBytes))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *FastFinalityRecovery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FastFinalityRecovery) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FastFinalityRecovery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	n6, err6 := github_com_cosmos_gogoproto_types.StdDurationMarshalTo(m.RetryInterval, dAtA[i-github_com_cosmos_gogoproto_types.SizeOfStdDuration(m.RetryInterval):])
+	if err6 != nil {
+		return 0, err6
+	}
+	i -= n6
+	i = encodeVarintConfig(dAtA, i, uint64(n6))
+	i--
+	dAtA[i] = 0x22
+	if m.MaxRetries != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxRetries))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.WindowExtension != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.WindowExtension))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Strategy != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.Strategy))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *Fraction) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -327,28 +767,95 @@ func (m *ProverConfig) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovConfig(uint64(l))
 	}
+	if m.RevisionNumber != 0 {
+		n += 1 + sovConfig(uint64(m.RevisionNumber))
+	}
+	if m.FastFinalityRecovery != nil {
+		l = m.FastFinalityRecovery.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.SubmissionInterval != 0 {
+		n += 1 + sovConfig(uint64(m.SubmissionInterval))
+	}
+	if m.UpdateBudget != nil {
+		l = m.UpdateBudget.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.HeaderQueryConcurrency != 0 {
+		n += 1 + sovConfig(uint64(m.HeaderQueryConcurrency))
+	}
+	if m.MaxFinalitySearchDepth != 0 {
+		n += 1 + sovConfig(uint64(m.MaxFinalitySearchDepth))
+	}
+	if m.UseFinalizedTag {
+		n += 2
+	}
+	if m.FinalizedTagTolerance != 0 {
+		n += 1 + sovConfig(uint64(m.FinalizedTagTolerance))
+	}
 	return n
 }
 
-func (m *Fraction) Size() (n int) {
+func (m *UpdateBudget) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.Numerator != 0 {
-		n += 1 + sovConfig(uint64(m.Numerator))
+	if m.MaxBytes != 0 {
+		n += 1 + sovConfig(uint64(m.MaxBytes))
 	}
-	if m.Denominator != 0 {
-		n += 1 + sovConfig(uint64(m.Denominator))
+	if m.MaxGas != 0 {
+		n += 1 + sovConfig(uint64(m.MaxGas))
+	}
+	if m.GasPerHeader != 0 {
+		n += 1 + sovConfig(uint64(m.GasPerHeader))
+	}
+	if m.GasPerByte != 0 {
+		n += 1 + sovConfig(uint64(m.GasPerByte))
 	}
 	return n
 }
 
-func sovConfig(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
+func (m *FastFinalityRecovery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Strategy != 0 {
+		n += 1 + sovConfig(uint64(m.Strategy))
+	}
+	if m.WindowExtension != 0 {
+		n += 1 + sovConfig(uint64(m.WindowExtension))
+	}
+	if m.MaxRetries != 0 {
+		n += 1 + sovConfig(uint64(m.MaxRetries))
+	}
+	l = github_com_cosmos_gogoproto_types.SizeOfStdDuration(m.RetryInterval)
+	n += 1 + l + sovConfig(uint64(l))
+	return n
 }
-func sozConfig(x uint64) (n int) {
+
+func (m *Fraction) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Numerator != 0 {
+		n += 1 + sovConfig(uint64(m.Numerator))
+	}
+	if m.Denominator != 0 {
+		n += 1 + sovConfig(uint64(m.Denominator))
+	}
+	return n
+}
+
+func sovConfig(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozConfig(x uint64) (n int) {
 	return sovConfig(uint64((x << 1) ^ uint64((int64(x) >> 63))))
 }
 func (m *ProverConfig) Unmarshal(dAtA []byte) error {
@@ -533,6 +1040,459 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 			}
 			m.Network = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RevisionNumber", wireType)
+			}
+			m.RevisionNumber = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RevisionNumber |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FastFinalityRecovery", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.FastFinalityRecovery == nil {
+				m.FastFinalityRecovery = &FastFinalityRecovery{}
+			}
+			if err := m.FastFinalityRecovery.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SubmissionInterval", wireType)
+			}
+			m.SubmissionInterval = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SubmissionInterval |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UpdateBudget", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.UpdateBudget == nil {
+				m.UpdateBudget = &UpdateBudget{}
+			}
+			if err := m.UpdateBudget.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeaderQueryConcurrency", wireType)
+			}
+			m.HeaderQueryConcurrency = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HeaderQueryConcurrency |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxFinalitySearchDepth", wireType)
+			}
+			m.MaxFinalitySearchDepth = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxFinalitySearchDepth |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UseFinalizedTag", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.UseFinalizedTag = bool(v != 0)
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FinalizedTagTolerance", wireType)
+			}
+			m.FinalizedTagTolerance = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FinalizedTagTolerance |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UpdateBudget) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UpdateBudget: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UpdateBudget: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxBytes", wireType)
+			}
+			m.MaxBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxGas", wireType)
+			}
+			m.MaxGas = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxGas |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GasPerHeader", wireType)
+			}
+			m.GasPerHeader = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.GasPerHeader |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GasPerByte", wireType)
+			}
+			m.GasPerByte = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.GasPerByte |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FastFinalityRecovery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FastFinalityRecovery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FastFinalityRecovery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Strategy", wireType)
+			}
+			m.Strategy = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Strategy |= FastFinalityRecovery_Strategy(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WindowExtension", wireType)
+			}
+			m.WindowExtension = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WindowExtension |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxRetries", wireType)
+			}
+			m.MaxRetries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxRetries |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RetryInterval", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_cosmos_gogoproto_types.StdDurationUnmarshal(&m.RetryInterval, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
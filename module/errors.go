@@ -0,0 +1,67 @@
+package module
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+var (
+	// ErrTrustedStateExpired is returned when the consensus state trusted by the counterparty client is older than the trusting period.
+	ErrTrustedStateExpired = errors.New("trusted consensus state expired")
+	// ErrHeaderFromFuture is returned when the target header timestamp exceeds the current time plus the max clock drift.
+	ErrHeaderFromFuture = errors.New("header from future")
+	// ErrInsufficientVoteAttestation is returned when no finalized header is found for an intermediate header even after the FastFinalityRecovery.
+	ErrInsufficientVoteAttestation = errors.New("insufficient vote attestation")
+	// ErrHeaderTooLarge is returned when a single header exceeds the UpdateBudget.
+	ErrHeaderTooLarge = errors.New("header too large")
+)
+
+// maxFinalityRejections is the number of the rejected candidates kept in FinalityNotFoundError.
+const maxFinalityRejections = 16
+
+// FinalityNotFoundError is returned when no finalized header is found within the search depth.
+// It usually means that the fast finality of the chain has stalled.
+type FinalityNotFoundError struct {
+	LatestBlockNumber uint64
+	// LowestBlockNumber is the lowest block searched
+	LowestBlockNumber uint64
+	// LastAttestation is the vote data of the highest block with a vote attestation, or nil if none is found
+	LastAttestationHeight uint64
+	LastAttestation       *VoteData
+	// Rejections are the first candidates rejected, up to maxFinalityRejections.
+	// They are the highest ones since the search goes backward from the latest block.
+	Rejections []FinalityRejection
+	// RejectedCount is the number of all the candidates rejected
+	RejectedCount int
+}
+
+// FinalityRejection is the reason why the source of the vote attestation at the height is not finalized.
+type FinalityRejection struct {
+	Height       uint64
+	SourceNumber uint64
+	Reason       string
+}
+
+func (e *FinalityNotFoundError) reject(height uint64, sourceNumber uint64, reason string) {
+	e.RejectedCount++
+	if len(e.Rejections) < maxFinalityRejections {
+		e.Rejections = append(e.Rejections, FinalityRejection{Height: height, SourceNumber: sourceNumber, Reason: reason})
+	}
+}
+
+func (e *FinalityNotFoundError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "no finalized header found: %d, searched down to %d", e.LatestBlockNumber, e.LowestBlockNumber)
+	if e.LastAttestation == nil {
+		b.WriteString(", no vote attestation")
+		return b.String()
+	}
+	fmt.Fprintf(&b, ", last attestation: height=%d, source=%d, target=%d", e.LastAttestationHeight, e.LastAttestation.SourceNumber, e.LastAttestation.TargetNumber)
+	fmt.Fprintf(&b, ", rejected=%d", e.RejectedCount)
+	for _, r := range e.Rejections {
+		fmt.Fprintf(&b, ", [height=%d, source=%d: %s]", r.Height, r.SourceNumber, r.Reason)
+	}
+	return b.String()
+}
@@ -0,0 +1,159 @@
+package module
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// maxDiagnosedCandidates is the number of candidates recorded in a diagnosis
+	maxDiagnosedCandidates = 32
+	// maxDiagnosedChildren is the number of children recorded for a candidate
+	maxDiagnosedChildren = 32
+)
+
+// FinalityDiagnosis explains why the header at the height is finalizable or not with the blocks up to the limit height.
+type FinalityDiagnosis struct {
+	Height      uint64 `json:"height"`
+	LimitHeight uint64 `json:"limit_height"`
+	Finalized   bool   `json:"finalized"`
+	// FinalizedHeight is the height of the candidate justified by the child and the grandchild
+	FinalizedHeight  uint64 `json:"finalized_height,omitempty"`
+	ChildHeight      uint64 `json:"child_height,omitempty"`
+	GrandChildHeight uint64 `json:"grand_child_height,omitempty"`
+	// Candidates are the blocks examined to be justified, from the height
+	Candidates []*FinalityCandidate `json:"candidates"`
+	// OmittedCandidates is the number of candidates examined but not recorded beyond maxDiagnosedCandidates
+	OmittedCandidates uint64 `json:"omitted_candidates,omitempty"`
+}
+
+// FinalityCandidate is a block examined to be justified by its descendants.
+type FinalityCandidate struct {
+	Height   uint64           `json:"height"`
+	Hash     common.Hash      `json:"hash"`
+	Children []*FinalityChild `json:"children"`
+	// OmittedChildren is the number of children examined but not recorded beyond maxDiagnosedChildren
+	OmittedChildren uint64 `json:"omitted_children,omitempty"`
+}
+
+// FinalityChild is a descendant examined to justify the candidate.
+type FinalityChild struct {
+	FinalityVote
+	GrandChildren []*FinalityVote `json:"grand_children,omitempty"`
+	// CutOffHeight is the height of the grandchild not examined because it is further than KAncestorGenerationDepth from the child
+	CutOffHeight             uint64 `json:"cut_off_height,omitempty"`
+	KAncestorGenerationDepth uint32 `json:"k_ancestor_generation_depth,omitempty"`
+}
+
+// FinalityVote is the vote attestation of a descendant and the linkage condition it failed.
+type FinalityVote struct {
+	Height       uint64       `json:"height"`
+	Hash         common.Hash  `json:"hash"`
+	SourceNumber *uint64      `json:"source_number,omitempty"`
+	SourceHash   *common.Hash `json:"source_hash,omitempty"`
+	TargetNumber *uint64      `json:"target_number,omitempty"`
+	TargetHash   *common.Hash `json:"target_hash,omitempty"`
+	// Failure is the linkage condition the vote failed, or empty if it links
+	Failure string `json:"failure,omitempty"`
+}
+
+// DiagnoseFinality searches the finality of the header at the height as queryFinalizedHeader does and records every block examined.
+// If `limitHeight` is 0, the height plus the submission interval is used as the prover searches, up to the latest height.
+func (pr *Prover) DiagnoseFinality(ctx context.Context, height uint64, limitHeight uint64) (*FinalityDiagnosis, error) {
+	if limitHeight == 0 {
+		latestHeight, err := pr.chain.LatestHeight(ctx)
+		if err != nil {
+			return nil, err
+		}
+		submissionInterval, err := pr.submissionInterval(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+		limitHeight = minUint64(height+submissionInterval, latestHeight.GetRevisionHeight())
+	}
+	if height > limitHeight {
+		return nil, fmt.Errorf("height %d is greater than the limit height %d", height, limitHeight)
+	}
+	diagnosis := &FinalityDiagnosis{Height: height, LimitHeight: limitHeight}
+	if _, err := queryFinalizedHeaderWithDiagnosis(ctx, pr.chain.Header, height, limitHeight, pr.getForkParameters(), diagnosis); err != nil {
+		return nil, err
+	}
+	return diagnosis, nil
+}
+
+func (d *FinalityDiagnosis) addCandidate(block *types.Header) *FinalityCandidate {
+	if d == nil {
+		return nil
+	}
+	if len(d.Candidates) >= maxDiagnosedCandidates {
+		d.OmittedCandidates++
+		return nil
+	}
+	candidate := &FinalityCandidate{Height: block.Number.Uint64(), Hash: block.Hash()}
+	d.Candidates = append(d.Candidates, candidate)
+	return candidate
+}
+
+func (d *FinalityDiagnosis) finalize(candidate, child, grandChild *types.Header) {
+	if d == nil {
+		return
+	}
+	d.Finalized = true
+	d.FinalizedHeight = candidate.Number.Uint64()
+	d.ChildHeight = child.Number.Uint64()
+	d.GrandChildHeight = grandChild.Number.Uint64()
+}
+
+func (c *FinalityCandidate) addChild(block *types.Header, vote *VoteAttestation, failure string) *FinalityChild {
+	if c == nil {
+		return nil
+	}
+	if len(c.Children) >= maxDiagnosedChildren {
+		c.OmittedChildren++
+		return nil
+	}
+	child := &FinalityChild{FinalityVote: *newFinalityVote(block, vote, failure)}
+	c.Children = append(c.Children, child)
+	return child
+}
+
+func (c *FinalityChild) addGrandChild(block *types.Header, vote *VoteAttestation, failure string) {
+	if c == nil {
+		return
+	}
+	c.GrandChildren = append(c.GrandChildren, newFinalityVote(block, vote, failure))
+}
+
+func (c *FinalityChild) cutOff(height uint64, depth uint32) {
+	if c == nil {
+		return
+	}
+	c.CutOffHeight = height
+	c.KAncestorGenerationDepth = depth
+}
+
+// unjustified records that no grandchild justifies the child which links to the candidate.
+func (c *FinalityChild) unjustified() {
+	if c == nil {
+		return
+	}
+	if c.CutOffHeight > 0 {
+		c.Failure = "no grandchild justifies the child within k_ancestor_generation_depth"
+	} else {
+		c.Failure = "no grandchild justifies the child up to the limit height"
+	}
+}
+
+func newFinalityVote(block *types.Header, vote *VoteAttestation, failure string) *FinalityVote {
+	v := &FinalityVote{Height: block.Number.Uint64(), Hash: block.Hash(), Failure: failure}
+	if vote != nil {
+		v.SourceNumber = &vote.Data.SourceNumber
+		v.SourceHash = &vote.Data.SourceHash
+		v.TargetNumber = &vote.Data.TargetNumber
+		v.TargetHash = &vote.Data.TargetHash
+	}
+	return v
+}
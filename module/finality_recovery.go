@@ -0,0 +1,107 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// withFastFinalityRecovery applies the configured FastFinalityRecovery when `query` finds no finalized header in the window
+// of `submissionInterval` blocks. Unless the strategy is STRATEGY_PARTIAL, ErrInsufficientVoteAttestation is returned if the recovery fails.
+// STRATEGY_FAIL is used unless the strategy is specified. `chainID` is only used for the metrics.
+func (pr *Prover) withFastFinalityRecovery(query queryVerifiableNeighboringEpochHeaderFn, chainID string, submissionInterval uint64) queryVerifiableNeighboringEpochHeaderFn {
+	recovery := pr.config.GetFastFinalityRecovery()
+	strategy := recovery.GetStrategy()
+	if strategy == FastFinalityRecovery_STRATEGY_UNSPECIFIED {
+		strategy = FastFinalityRecovery_STRATEGY_FAIL
+	}
+	return func(ctx context.Context, height uint64, limitHeight uint64) (core.Header, error) {
+		header, err := query(ctx, height, limitHeight)
+		if err != nil || header != nil {
+			return header, err
+		}
+		logger := log.GetLogger()
+		switch strategy {
+		case FastFinalityRecovery_STRATEGY_WIDEN_WINDOW:
+			header, err = pr.widenFinalityWindow(ctx, query, height, limitHeight, recovery.GetWindowExtension(), submissionInterval)
+		case FastFinalityRecovery_STRATEGY_RETRY:
+			header, err = pr.retryFinality(ctx, query, height, recovery.GetMaxRetries(), recovery.GetRetryInterval(), submissionInterval)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		result := "failed"
+		if header != nil {
+			result = "recovered"
+		} else if strategy == FastFinalityRecovery_STRATEGY_PARTIAL {
+			result = "partial"
+		}
+		fastFinalityErrorCounter.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("chain_id", chainID),
+			attribute.String("strategy", strategyName(strategy)),
+			attribute.String("result", result),
+		))
+		switch result {
+		case "recovered":
+			logger.InfoContext(ctx, "finalized header found by the recovery", "strategy", strategyName(strategy), "height", height, "finalized", header.GetHeight().GetRevisionHeight())
+			return header, nil
+		case "partial":
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: height=%d, limit=%d, strategy=%s", ErrInsufficientVoteAttestation, height, limitHeight, strategyName(strategy))
+	}
+}
+
+// widenFinalityWindow searches the descendants proving the finality up to `extension` blocks beyond the window.
+// The finalized header itself must be within the original window, which is in the same epoch as `height`.
+func (pr *Prover) widenFinalityWindow(ctx context.Context, query queryVerifiableNeighboringEpochHeaderFn, height uint64, limitHeight uint64, extension uint64, submissionInterval uint64) (core.Header, error) {
+	latestHeight, err := pr.chain.LatestHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	widened := minUint64(limitHeight+extension, latestHeight.GetRevisionHeight())
+	if widened <= limitHeight {
+		return nil, nil
+	}
+	header, err := query(ctx, height, widened)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	if header.GetHeight().GetRevisionHeight() >= height+submissionInterval {
+		log.GetLogger().DebugContext(ctx, "finalized header is out of the epoch", "height", height, "finalized", header.GetHeight().GetRevisionHeight())
+		return nil, nil
+	}
+	return header, nil
+}
+
+// retryFinality waits for `interval` and searches again up to `maxRetries` times with the window extended by the new blocks.
+func (pr *Prover) retryFinality(ctx context.Context, query queryVerifiableNeighboringEpochHeaderFn, height uint64, maxRetries uint32, interval time.Duration, submissionInterval uint64) (core.Header, error) {
+	for i := uint32(0); i < maxRetries; i++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		latestHeight, err := pr.chain.LatestHeight(ctx)
+		if err != nil {
+			return nil, err
+		}
+		header, err := query(ctx, height, minUint64(height+submissionInterval, latestHeight.GetRevisionHeight()))
+		if err != nil || header != nil {
+			return header, err
+		}
+		log.GetLogger().DebugContext(ctx, "finalized header not found", "height", height, "retry", i+1, "maxRetries", maxRetries)
+	}
+	return nil, nil
+}
+
+func strategyName(strategy FastFinalityRecovery_Strategy) string {
+	return strings.ToLower(strings.TrimPrefix(strategy.String(), "STRATEGY_"))
+}
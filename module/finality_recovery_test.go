@@ -0,0 +1,146 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"github.com/stretchr/testify/suite"
+)
+
+type recoveryChain struct {
+	Chain
+	latestHeight uint64
+}
+
+func (c *recoveryChain) LatestHeight(_ context.Context) (exported.Height, error) {
+	return clienttypes.NewHeight(0, c.latestHeight), nil
+}
+
+type FinalityRecoveryTestSuite struct {
+	suite.Suite
+}
+
+func TestFinalityRecoveryTestSuite(t *testing.T) {
+	suite.Run(t, new(FinalityRecoveryTestSuite))
+}
+
+func (ts *FinalityRecoveryTestSuite) SetupTest() {
+	err := log.InitLogger("DEBUG", "text", "stdout", false)
+	ts.Require().NoError(err)
+}
+
+func (ts *FinalityRecoveryTestSuite) prover(latestHeight uint64, recovery *FastFinalityRecovery) *Prover {
+	return &Prover{
+		chain:  &recoveryChain{latestHeight: latestHeight},
+		config: &ProverConfig{Network: string(Localnet), FastFinalityRecovery: recovery},
+	}
+}
+
+// finalizedAt returns a query which finds the finalized header at `finalized` if the limit height is at least `requiredLimit`
+func (ts *FinalityRecoveryTestSuite) finalizedAt(finalized uint64, requiredLimit uint64, limits *[]uint64) queryVerifiableNeighboringEpochHeaderFn {
+	return func(_ context.Context, height uint64, limitHeight uint64) (core.Header, error) {
+		*limits = append(*limits, limitHeight)
+		if limitHeight < requiredLimit {
+			return nil, nil
+		}
+		ethHeader, err := newETHHeader(&types.Header{Number: big.NewInt(int64(finalized))})
+		ts.Require().NoError(err)
+		return &Header{Headers: []*ETHHeader{ethHeader}}, nil
+	}
+}
+
+func (ts *FinalityRecoveryTestSuite) TestDefault() {
+	var limits []uint64
+	_, err := ts.prover(5000, nil).withFastFinalityRecovery(ts.finalizedAt(1500, 3000, &limits), "9999", skip)(context.Background(), 1000, 2000)
+	ts.Require().True(errors.Is(err, ErrInsufficientVoteAttestation))
+	ts.Require().Equal([]uint64{2000}, limits)
+
+	// the recovery without the strategy fails as well
+	limits = nil
+	recovery := &FastFinalityRecovery{WindowExtension: 10}
+	_, err = ts.prover(5000, recovery).withFastFinalityRecovery(ts.finalizedAt(1500, 3000, &limits), "9999", skip)(context.Background(), 1000, 2000)
+	ts.Require().True(errors.Is(err, ErrInsufficientVoteAttestation))
+	ts.Require().Equal([]uint64{2000}, limits)
+}
+
+func (ts *FinalityRecoveryTestSuite) TestPartial() {
+	var limits []uint64
+	recovery := &FastFinalityRecovery{Strategy: FastFinalityRecovery_STRATEGY_PARTIAL}
+	header, err := ts.prover(5000, recovery).withFastFinalityRecovery(ts.finalizedAt(1500, 3000, &limits), "9999", skip)(context.Background(), 1000, 2000)
+	ts.Require().NoError(err)
+	ts.Require().Nil(header)
+	ts.Require().Equal([]uint64{2000}, limits)
+}
+
+func (ts *FinalityRecoveryTestSuite) TestFail() {
+	var limits []uint64
+	recovery := &FastFinalityRecovery{Strategy: FastFinalityRecovery_STRATEGY_FAIL}
+	_, err := ts.prover(5000, recovery).withFastFinalityRecovery(ts.finalizedAt(1500, 3000, &limits), "9999", skip)(context.Background(), 1000, 2000)
+	ts.Require().True(errors.Is(err, ErrInsufficientVoteAttestation))
+}
+
+func (ts *FinalityRecoveryTestSuite) TestWidenWindow() {
+	var limits []uint64
+	recovery := &FastFinalityRecovery{Strategy: FastFinalityRecovery_STRATEGY_WIDEN_WINDOW, WindowExtension: 10}
+	header, err := ts.prover(5000, recovery).withFastFinalityRecovery(ts.finalizedAt(1990, 2005, &limits), "9999", skip)(context.Background(), 1000, 2000)
+	ts.Require().NoError(err)
+	ts.Require().Equal(uint64(1990), header.GetHeight().GetRevisionHeight())
+	ts.Require().Equal([]uint64{2000, 2010}, limits)
+
+	// limited by the latest height
+	limits = nil
+	_, err = ts.prover(2003, recovery).withFastFinalityRecovery(ts.finalizedAt(1990, 2005, &limits), "9999", skip)(context.Background(), 1000, 2000)
+	ts.Require().True(errors.Is(err, ErrInsufficientVoteAttestation))
+	ts.Require().Equal([]uint64{2000, 2003}, limits)
+
+	// finalized header out of the epoch
+	limits = nil
+	_, err = ts.prover(5000, recovery).withFastFinalityRecovery(ts.finalizedAt(2000, 2005, &limits), "9999", skip)(context.Background(), 1000, 2000)
+	ts.Require().True(errors.Is(err, ErrInsufficientVoteAttestation))
+}
+
+func (ts *FinalityRecoveryTestSuite) TestRetry() {
+	var limits []uint64
+	recovery := &FastFinalityRecovery{Strategy: FastFinalityRecovery_STRATEGY_RETRY, MaxRetries: 2, RetryInterval: time.Millisecond}
+	prover := ts.prover(1500, recovery)
+	query := ts.finalizedAt(1400, 1600, &limits)
+	_, err := prover.withFastFinalityRecovery(query, "9999", skip)(context.Background(), 1000, 1500)
+	ts.Require().True(errors.Is(err, ErrInsufficientVoteAttestation))
+	ts.Require().Equal([]uint64{1500, 1500, 1500}, limits)
+
+	// the chain proceeds
+	limits = nil
+	prover.chain.(*recoveryChain).latestHeight = 1700
+	header, err := prover.withFastFinalityRecovery(query, "9999", skip)(context.Background(), 1000, 1500)
+	ts.Require().NoError(err)
+	ts.Require().Equal(uint64(1400), header.GetHeight().GetRevisionHeight())
+	ts.Require().Equal([]uint64{1500, 1700}, limits)
+
+	// canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	prover.chain.(*recoveryChain).latestHeight = 1500
+	_, err = prover.withFastFinalityRecovery(query, "9999", skip)(ctx, 1000, 1500)
+	ts.Require().ErrorIs(err, context.Canceled)
+}
+
+func (ts *FinalityRecoveryTestSuite) TestValidate() {
+	config := &ProverConfig{Network: string(Localnet)}
+	ts.Require().NoError(config.Validate())
+	config.FastFinalityRecovery = &FastFinalityRecovery{Strategy: FastFinalityRecovery_STRATEGY_WIDEN_WINDOW}
+	ts.Require().Error(config.Validate())
+	config.FastFinalityRecovery.WindowExtension = 100
+	ts.Require().NoError(config.Validate())
+	config.FastFinalityRecovery = &FastFinalityRecovery{Strategy: FastFinalityRecovery_STRATEGY_RETRY, MaxRetries: 1}
+	ts.Require().Error(config.Validate())
+	config.FastFinalityRecovery.RetryInterval = time.Second
+	ts.Require().NoError(config.Validate())
+}
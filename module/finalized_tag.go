@@ -0,0 +1,77 @@
+package module
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/hyperledger-labs/yui-relayer/log"
+)
+
+// FinalizedHeaderQuerier is implemented by the chain whose node exposes the `finalized` block tag based on the fast finality.
+type FinalizedHeaderQuerier interface {
+	FinalizedHeader(ctx context.Context) (*types.Header, error)
+}
+
+// queryLatestFinalizedHeader returns the latest finalized header derived from the vote attestations.
+// If UseFinalizedTag is set, only the blocks above the finalized block reported by the node are searched,
+// and the reported one is verified instead of searching backward further.
+func (pr *Prover) queryLatestFinalizedHeader(ctx context.Context, latestBlockNumber uint64) (uint64, []*ETHHeader, error) {
+	forkSpecs := pr.getForkParameters()
+	querier, ok := pr.chain.(FinalizedHeaderQuerier)
+	if !pr.config.GetUseFinalizedTag() || !ok {
+		return queryLatestFinalizedHeader(ctx, pr.chain.Header, latestBlockNumber, forkSpecs, pr.finalitySearchDepth())
+	}
+	logger := log.GetLogger()
+	tagged, err := querier.FinalizedHeader(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to query the finalized block tag", err)
+		return queryLatestFinalizedHeader(ctx, pr.chain.Header, latestBlockNumber, forkSpecs, pr.finalitySearchDepth())
+	}
+	taggedHeight := tagged.Number.Uint64()
+	if taggedHeight > latestBlockNumber {
+		// The node is ahead of the latest block the search is based on
+		taggedHeight = latestBlockNumber
+	}
+
+	var height uint64
+	var headers []*ETHHeader
+	if depth := latestBlockNumber - taggedHeight; depth > 0 {
+		height, headers, err = searchLatestFinalizedHeader(ctx, pr.chain.Header, latestBlockNumber, forkSpecs, depth)
+		var notFound *FinalityNotFoundError
+		if err != nil && !errors.As(err, &notFound) {
+			return 0, nil, err
+		}
+	}
+	if headers == nil {
+		// The descendants of the reported block are searched within the same depth as the backward search
+		limitHeight := minUint64(taggedHeight+pr.finalitySearchDepth(), latestBlockNumber)
+		if headers, err = queryFinalizedHeader(ctx, pr.chain.Header, taggedHeight, limitHeight, forkSpecs); err != nil {
+			return 0, nil, err
+		}
+		height = taggedHeight
+		if headers == nil {
+			// The divergence is alerted once here, so the derived height is not checked against the reported one again
+			logger.ErrorContext(ctx, "[FinalizedTagDivergence]", fmt.Errorf("finalized block reported by the node is not verifiable: finalized=%d, latest=%d", taggedHeight, latestBlockNumber))
+			finalizedTagDivergenceCounter.Add(ctx, 1)
+			return queryLatestFinalizedHeader(ctx, pr.chain.Header, latestBlockNumber, forkSpecs, pr.finalitySearchDepth())
+		}
+	}
+	pr.checkFinalizedTag(ctx, height, taggedHeight)
+	return height, headers, nil
+}
+
+// checkFinalizedTag alerts if the finalized height reported by the node diverges from the derived one beyond the tolerance.
+func (pr *Prover) checkFinalizedTag(ctx context.Context, derivedHeight uint64, taggedHeight uint64) bool {
+	diff := derivedHeight - taggedHeight
+	if taggedHeight > derivedHeight {
+		diff = taggedHeight - derivedHeight
+	}
+	if diff <= pr.config.GetFinalizedTagTolerance() {
+		return true
+	}
+	log.GetLogger().ErrorContext(ctx, "[FinalizedTagDivergence]", fmt.Errorf("finalized height reported by the node diverges: derived=%d, finalized=%d, tolerance=%d", derivedHeight, taggedHeight, pr.config.GetFinalizedTagTolerance()))
+	finalizedTagDivergenceCounter.Add(ctx, 1)
+	return false
+}
@@ -0,0 +1,84 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"github.com/stretchr/testify/suite"
+)
+
+type finalizedTagChain struct {
+	Chain
+	finalized uint64
+	err       error
+}
+
+func (c *finalizedTagChain) Header(_ context.Context, height uint64) (*types.Header, error) {
+	if h := headerByHeight(int64(height)); h != nil {
+		return h, nil
+	}
+	return &types.Header{Number: big.NewInt(int64(height))}, nil
+}
+
+func (c *finalizedTagChain) FinalizedHeader(ctx context.Context) (*types.Header, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.Header(ctx, c.finalized)
+}
+
+type FinalizedTagTestSuite struct {
+	suite.Suite
+	prover *Prover
+	chain  *finalizedTagChain
+}
+
+func TestFinalizedTagTestSuite(t *testing.T) {
+	suite.Run(t, new(FinalizedTagTestSuite))
+}
+
+func (ts *FinalizedTagTestSuite) SetupTest() {
+	err := log.InitLogger("DEBUG", "text", "stdout", false)
+	ts.Require().NoError(err)
+	ts.chain = &finalizedTagChain{}
+	ts.prover = NewProver(ts.chain, &ProverConfig{Network: string(Localnet), UseFinalizedTag: true}).(*Prover)
+}
+
+func (ts *FinalizedTagTestSuite) TestQueryLatestFinalizedHeader() {
+	ctx := context.Background()
+	verify := func(finalized uint64, err error) {
+		ts.chain.finalized = finalized
+		ts.chain.err = err
+		height, headers, err := ts.prover.queryLatestFinalizedHeader(ctx, 1003)
+		ts.Require().NoError(err)
+		ts.Require().Equal(uint64(1001), height)
+		ts.Require().Len(headers, 3)
+	}
+	// Found above the finalized block reported by the node
+	verify(1000, nil)
+	verify(1001, nil)
+	// The reported one is not verifiable, so the backward search is used
+	verify(1003, nil)
+	verify(2000, nil)
+	// The node does not support the tag
+	verify(0, fmt.Errorf("unsupported block tag"))
+
+	ts.prover.config.UseFinalizedTag = false
+	verify(0, nil)
+}
+
+func (ts *FinalizedTagTestSuite) TestCheckFinalizedTag() {
+	ctx := context.Background()
+	ts.Require().True(ts.prover.checkFinalizedTag(ctx, 1001, 1001))
+	ts.Require().False(ts.prover.checkFinalizedTag(ctx, 1001, 1000))
+	ts.Require().False(ts.prover.checkFinalizedTag(ctx, 1000, 1001))
+
+	ts.prover.config.FinalizedTagTolerance = 1
+	ts.Require().True(ts.prover.checkFinalizedTag(ctx, 1001, 1000))
+	ts.Require().True(ts.prover.checkFinalizedTag(ctx, 1000, 1001))
+	ts.Require().False(ts.prover.checkFinalizedTag(ctx, 1003, 1001))
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"slices"
 	"strconv"
+	"sync"
 
 	"github.com/cockroachdb/errors"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -216,6 +217,21 @@ func (be BoundaryEpochs) PreviousEpochBlockNumber(currentEpochBlockNumber uint64
 	return currentEpochBlockNumber - be.CurrentForkSpec.EpochLength
 }
 
+func (be BoundaryEpochs) NextEpochBlockNumber(currentEpochBlockNumber uint64) uint64 {
+	if currentEpochBlockNumber >= be.CurrentFirst {
+		return currentEpochBlockNumber + be.CurrentForkSpec.EpochLength
+	}
+	if currentEpochBlockNumber < be.PrevLast {
+		return currentEpochBlockNumber + be.PreviousForkSpec.EpochLength
+	}
+	for _, mid := range be.Intermediates {
+		if mid > currentEpochBlockNumber {
+			return mid
+		}
+	}
+	return be.CurrentFirst
+}
+
 func FindTargetForkSpec(forkSpecs []*ForkSpec, height uint64, timestamp uint64) (*ForkSpec, []*ForkSpec, error) {
 	reversed := make([]*ForkSpec, len(forkSpecs))
 	for i, spec := range forkSpecs {
@@ -243,7 +259,11 @@ func FindTargetForkSpec(forkSpecs []*ForkSpec, height uint64, timestamp uint64)
 	return nil, nil, fmt.Errorf("no fork spec found height=%d, timestmp=%d", height, timestamp)
 }
 
-var boundaryHeightCache = make(map[uint64]uint64)
+// boundaryHeightCache is shared by the headers queried concurrently, so it is accessed with boundaryHeightCacheMu held.
+var (
+	boundaryHeightCache   = make(map[uint64]uint64)
+	boundaryHeightCacheMu sync.Mutex
+)
 
 func GetBoundaryHeight(ctx context.Context, headerFn getHeaderFn, currentHeight uint64, currentForkSpec ForkSpec) (*BoundaryHeight, error) {
 	var err error
@@ -253,7 +273,10 @@ func GetBoundaryHeight(ctx context.Context, headerFn getHeaderFn, currentHeight
 		boundaryHeight = condition.Height
 	} else {
 		ts := currentForkSpec.GetTimestamp()
-		if v, ok := boundaryHeightCache[ts]; ok {
+		boundaryHeightCacheMu.Lock()
+		v, ok := boundaryHeightCache[ts]
+		boundaryHeightCacheMu.Unlock()
+		if ok {
 			boundaryHeight = v
 		} else {
 			logger.DebugContext(ctx, "seek fork height", "currentHeight", currentHeight, "ts", ts)
@@ -261,7 +284,9 @@ func GetBoundaryHeight(ctx context.Context, headerFn getHeaderFn, currentHeight
 			if err != nil {
 				return nil, err
 			}
+			boundaryHeightCacheMu.Lock()
 			boundaryHeightCache[ts] = boundaryHeight
+			boundaryHeightCacheMu.Unlock()
 		}
 	}
 	return &BoundaryHeight{
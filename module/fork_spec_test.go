@@ -10,6 +10,7 @@ import (
 	"errors"
 
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
 	"github.com/hyperledger-labs/yui-relayer/log"
 	"github.com/stretchr/testify/suite"
 )
@@ -181,6 +182,42 @@ func (ts *ForkSpecTestSuite) Test_GetBoundaryHeight_ValidTimestampMultiHeaderNot
 	ts.Equal(uint64(1000), boundaryHeight.Height)
 }
 
+// Test_GetBoundaryHeight_Concurrent shares the cache among the workers of queryConcurrently as the intermediate headers do.
+// Run with -race to detect unsynchronized accesses.
+func (ts *ForkSpecTestSuite) Test_GetBoundaryHeight_Concurrent() {
+	headerFn := func(ctx context.Context, height uint64) (*types.Header, error) {
+		return &types.Header{Number: big.NewInt(int64(height)), Time: height}, nil
+	}
+	currentForkSpec := ForkSpec{HeightOrTimestamp: &ForkSpec_Timestamp{Timestamp: 1000_000}} // msec
+	heights := make([]uint64, 64)
+	for i := range heights {
+		heights[i] = 1100 + uint64(i)
+	}
+
+	var boundaryHeights []uint64
+	completed, err := queryConcurrently(context.Background(), heights, 8, func(ctx context.Context, height uint64) (core.Header, error) {
+		boundaryHeight, err := GetBoundaryHeight(ctx, headerFn, height, currentForkSpec)
+		if err != nil {
+			return nil, err
+		}
+		ethHeader, err := newETHHeader(&types.Header{Number: big.NewInt(int64(boundaryHeight.Height))})
+		if err != nil {
+			return nil, err
+		}
+		return &Header{Headers: []*ETHHeader{ethHeader}}, nil
+	}, func(_ uint64, h core.Header) (bool, error) {
+		boundaryHeights = append(boundaryHeights, h.GetHeight().GetRevisionHeight())
+		return true, nil
+	})
+
+	ts.Require().NoError(err)
+	ts.Require().True(completed)
+	ts.Require().Len(boundaryHeights, len(heights))
+	for _, boundaryHeight := range boundaryHeights {
+		ts.Require().Equal(uint64(1000), boundaryHeight)
+	}
+}
+
 func (ts *ForkSpecTestSuite) Test_GetBoundaryHeight_TimestampNotFound() {
 	headerFn := func(ctx context.Context, height uint64) (*types.Header, error) {
 		return &types.Header{Number: big.NewInt(int64(height)), Time: uint64(500)}, nil
@@ -399,6 +436,13 @@ func (ts *ForkSpecTestSuite) Test_Success_GetBoundaryEpochs_After_Maxwell() {
 	ts.Require().Equal(epochs.PreviousEpochBlockNumber(1000), uint64(500))
 	ts.Require().Equal(epochs.PreviousEpochBlockNumber(2000), uint64(1000))
 	ts.Require().Equal(epochs.PreviousEpochBlockNumber(3000), uint64(2000))
+
+	ts.Require().Equal(epochs.NextEpochBlockNumber(0), uint64(200))
+	ts.Require().Equal(epochs.NextEpochBlockNumber(200), uint64(400))
+	ts.Require().Equal(epochs.NextEpochBlockNumber(400), uint64(500))
+	ts.Require().Equal(epochs.NextEpochBlockNumber(500), uint64(1000))
+	ts.Require().Equal(epochs.NextEpochBlockNumber(1000), uint64(2000))
+	ts.Require().Equal(epochs.NextEpochBlockNumber(2000), uint64(3000))
 }
 
 func (ts *ForkSpecTestSuite) Test_Success_GetBoundaryEpochs_After_Maxwell_1() {
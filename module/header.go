@@ -2,9 +2,10 @@ package module
 
 import (
 	"fmt"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/holiman/uint256"
-	"log"
+	"github.com/hyperledger-labs/yui-relayer/log"
 
 	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
 	"github.com/cosmos/ibc-go/v8/modules/core/exported"
@@ -23,54 +24,160 @@ func (*Header) ClientType() string {
 	return Parlia
 }
 
+// maxHeadersLength is the maximum number of ETH headers in a Header.
+// The headers are the target and its descendants up to the grandchild which justifies the child,
+// so they span at most an epoch, which covers a submission window, and the KAncestorGenerationDepth blocks up to the grandchild.
+var maxHeadersLength = func() int {
+	var length uint64
+	for _, spec := range getForkSpecParams() {
+		length = max(length, spec.EpochLength+uint64(spec.KAncestorGenerationDepth)+2)
+	}
+	return int(length)
+}()
+
+// GetHeight returns the height of the target header.
+// Only the target header is decoded. Callers which need several ETH headers decode them once with decodeEthHeaders.
+// The revision number is inherited from TrustedHeight because a header never crosses revisions.
+// TrustedHeight is nil until SetupHeadersForUpdate assigns it, so the revision number is 0 before then
+// and only the revision height is comparable with the heights of the client state.
+// It returns a zero height for a malformed header. Use Height to handle the error.
 func (h *Header) GetHeight() exported.Height {
+	height, err := h.Height()
+	if err != nil {
+		log.GetLogger().Error("invalid header", err)
+		return clienttypes.ZeroHeight()
+	}
+	return height
+}
+
+// Height returns the height of the target header or an error if the header is malformed.
+func (h *Header) Height() (clienttypes.Height, error) {
 	target, err := h.Target()
 	if err != nil {
-		log.Panicf("invalid header: %v", h)
+		return clienttypes.ZeroHeight(), err
 	}
-	//TODO revision number
-	return clienttypes.NewHeight(0, target.Number.Uint64())
+	var revisionNumber uint64
+	if h.TrustedHeight != nil {
+		revisionNumber = h.TrustedHeight.RevisionNumber
+	}
+	return clienttypes.NewHeight(revisionNumber, target.Number.Uint64()), nil
+}
+
+// headerHashFields are the names of the 32-byte roots and hashes by their index in the RLP list of a header.
+var headerHashFields = map[int]string{
+	0:  "parent hash",
+	1:  "uncle hash",
+	3:  "state root",
+	4:  "transactions root",
+	5:  "receipts root",
+	13: "mix digest",
+	16: "withdrawals root",
+	19: "parent beacon root",
+	20: "requests hash",
 }
 
+// ValidateBasic checks that the number of the headers is within maxHeadersLength, the roots and hashes are 32 bytes,
+// the headers are contiguous from the target and the validators are well-formed.
 func (h *Header) ValidateBasic() error {
-	if _, err := h.Target(); err != nil {
+	if len(h.Headers) > maxHeadersLength {
+		return fmt.Errorf("too many headers: length=%d, max=%d", len(h.Headers), maxHeadersLength)
+	}
+	for i, e := range h.Headers {
+		if e == nil {
+			return fmt.Errorf("nil header: index=%d", i)
+		}
+		if err := validateHeaderHashes(e.Header); err != nil {
+			return fmt.Errorf("invalid header: index=%d, %w", i, err)
+		}
+	}
+	decodedHeaders, err := h.decodeEthHeaders()
+	if err != nil {
 		return err
 	}
+	if len(decodedHeaders) == 0 {
+		return fmt.Errorf("invalid header length")
+	}
+	for i := 1; i < len(decodedHeaders); i++ {
+		parent, child := decodedHeaders[i-1], decodedHeaders[i]
+		if child.Number.Uint64() != parent.Number.Uint64()+1 {
+			return fmt.Errorf("headers are not contiguous: index=%d, parent=%d, child=%d", i, parent.Number.Uint64(), child.Number.Uint64())
+		}
+		if child.ParentHash != parent.Hash() {
+			return fmt.Errorf("unexpected parent hash: index=%d, number=%d, expected=%s, actual=%s", i, child.Number.Uint64(), parent.Hash(), child.ParentHash)
+		}
+	}
+	if err = validateValidatorBytes(h.CurrentValidators); err != nil {
+		return fmt.Errorf("invalid current validators: %w", err)
+	}
+	if err = validateValidatorBytes(h.PreviousValidators); err != nil {
+		return fmt.Errorf("invalid previous validators: %w", err)
+	}
+	return nil
+}
+
+// validateHeaderHashes checks the length of the roots and hashes in the RLP-encoded header before it is decoded.
+func validateHeaderHashes(raw []byte) error {
+	content, _, err := rlp.SplitList(raw)
+	if err != nil {
+		return err
+	}
+	for i := 0; len(content) > 0; i++ {
+		kind, value, rest, err := rlp.Split(content)
+		if err != nil {
+			return err
+		}
+		if name, ok := headerHashFields[i]; ok && (kind != rlp.String || len(value) != common.HashLength) {
+			return fmt.Errorf("invalid %s length: %d", name, len(value))
+		}
+		content = rest
+	}
+	return nil
+}
+
+func validateValidatorBytes(validators [][]byte) error {
+	for i, v := range validators {
+		if len(v) != validatorBytesLength {
+			return fmt.Errorf("invalid validator bytes length: index=%d, length=%d", i, len(v))
+		}
+	}
 	return nil
 }
 
 func (h *Header) decodeEthHeaders() ([]*types.Header, error) {
 	ethHeaders := make([]*types.Header, len(h.Headers))
 	for i, e := range h.Headers {
-		var ethHeader types.Header
-		if err := rlp.DecodeBytes(e.Header, &ethHeader); err != nil {
-			return nil, err
+		ethHeader, err := e.decode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode header: index=%d, %w", i, err)
 		}
-		ethHeaders[i] = &ethHeader
+		ethHeaders[i] = ethHeader
 	}
 	return ethHeaders, nil
 }
 
-func (h *Header) Target() (*types.Header, error) {
-	decodedHeaders, err := h.decodeEthHeaders()
-	if err != nil {
+func (e *ETHHeader) decode() (*types.Header, error) {
+	if e == nil {
+		return nil, fmt.Errorf("nil header")
+	}
+	var ethHeader types.Header
+	if err := rlp.DecodeBytes(e.Header, &ethHeader); err != nil {
 		return nil, err
 	}
-	if len(decodedHeaders) == 0 {
+	return &ethHeader, nil
+}
+
+func (h *Header) Target() (*types.Header, error) {
+	if len(h.Headers) == 0 {
 		return nil, fmt.Errorf("invalid header length")
 	}
-	return decodedHeaders[0], nil
+	return h.Headers[0].decode()
 }
 
 func (h *Header) Last() (*types.Header, error) {
-	decodedHeaders, err := h.decodeEthHeaders()
-	if err != nil {
-		return nil, err
-	}
-	if len(decodedHeaders) == 0 {
+	if len(h.Headers) == 0 {
 		return nil, fmt.Errorf("invalid header length")
 	}
-	return decodedHeaders[len(decodedHeaders)-1], nil
+	return h.Headers[len(h.Headers)-1].decode()
 }
 
 func MilliTimestamp(h *types.Header) uint64 {
@@ -2,8 +2,8 @@ package module
 
 import (
 	"context"
-	"fmt"
 
+	"github.com/cockroachdb/errors"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/hyperledger-labs/yui-relayer/log"
@@ -11,9 +11,30 @@ import (
 
 type getHeaderFn func(context.Context, uint64) (*types.Header, error)
 
-func queryLatestFinalizedHeader(ctx context.Context, getHeader getHeaderFn, latestBlockNumber uint64, forkSpecs []*ForkSpec) (uint64, []*ETHHeader, error) {
+// queryLatestFinalizedHeader searches the latest finalized header backward from the latest block down to `maxDepth` blocks.
+// If `maxDepth` is 0, the search continues to the genesis. FinalityNotFoundError is returned if no finalized header is found.
+func queryLatestFinalizedHeader(ctx context.Context, getHeader getHeaderFn, latestBlockNumber uint64, forkSpecs []*ForkSpec, maxDepth uint64) (uint64, []*ETHHeader, error) {
+	height, headers, err := searchLatestFinalizedHeader(ctx, getHeader, latestBlockNumber, forkSpecs, maxDepth)
+	var notFound *FinalityNotFoundError
+	if errors.As(err, &notFound) {
+		finalityNotFoundCounter.Add(ctx, 1)
+	}
+	return height, headers, err
+}
+
+// searchLatestFinalizedHeader does what queryLatestFinalizedHeader does without counting the failure as a finality stall.
+func searchLatestFinalizedHeader(ctx context.Context, getHeader getHeaderFn, latestBlockNumber uint64, forkSpecs []*ForkSpec, maxDepth uint64) (uint64, []*ETHHeader, error) {
 	logger := log.GetLogger()
-	for i := latestBlockNumber; i > 0; i-- {
+	lowest := uint64(1)
+	if maxDepth > 0 && latestBlockNumber > maxDepth {
+		lowest = latestBlockNumber - maxDepth + 1
+	}
+	notFound := &FinalityNotFoundError{LatestBlockNumber: latestBlockNumber, LowestBlockNumber: lowest}
+	tried := make(map[uint64]bool)
+	for i := latestBlockNumber; i >= lowest && i > 0; i-- {
+		if err := ctx.Err(); err != nil {
+			return 0, nil, err
+		}
 		header, err := getHeader(ctx, i)
 		if err != nil {
 			return 0, nil, err
@@ -25,7 +46,16 @@ func queryLatestFinalizedHeader(ctx context.Context, getHeader getHeaderFn, late
 		if vote == nil {
 			continue
 		}
+		if notFound.LastAttestation == nil {
+			notFound.LastAttestationHeight = i
+			notFound.LastAttestation = vote.Data
+		}
 		probablyFinalized := vote.Data.SourceNumber
+		// The headers attesting the same source are justified by the same descendants
+		if tried[probablyFinalized] {
+			continue
+		}
+		tried[probablyFinalized] = true
 
 		logger.DebugContext(ctx, "Try to seek verifying headers to finalize", "probablyFinalized", probablyFinalized, "latest", latestBlockNumber)
 
@@ -36,9 +66,10 @@ func queryLatestFinalizedHeader(ctx context.Context, getHeader getHeaderFn, late
 		if headers != nil {
 			return probablyFinalized, headers, nil
 		}
+		notFound.reject(i, probablyFinalized, "no descendant up to the latest block justifies the source and its child")
 		logger.DebugContext(ctx, "Failed to seek verifying headers to finalize. So seek previous finalized header.", "probablyFinalized", probablyFinalized, "latest", latestBlockNumber)
 	}
-	return 0, nil, fmt.Errorf("no finalized header found: %d", latestBlockNumber)
+	return 0, nil, notFound
 }
 
 // queryFinalizedHeader returns finalized header sequence
@@ -52,6 +83,11 @@ func queryLatestFinalizedHeader(ctx context.Context, getHeader getHeaderFn, late
 // 72476712 -> target 72476710 -> target 72476708
 // 72476712 --------------------> source 72476708
 func queryFinalizedHeader(ctx context.Context, fn getHeaderFn, height uint64, limitHeight uint64, forkSpecs []*ForkSpec) ([]*ETHHeader, error) {
+	return queryFinalizedHeaderWithDiagnosis(ctx, fn, height, limitHeight, forkSpecs, nil)
+}
+
+// queryFinalizedHeaderWithDiagnosis does what queryFinalizedHeader does and records the blocks examined to the diagnosis if it is not nil.
+func queryFinalizedHeaderWithDiagnosis(ctx context.Context, fn getHeaderFn, height uint64, limitHeight uint64, forkSpecs []*ForkSpec, diagnosis *FinalityDiagnosis) ([]*ETHHeader, error) {
 	var ethHeaders []*ETHHeader
 	for i := height; i+2 <= limitHeight; i++ {
 		finalizedBlock, finalizedETHHeader, _, err := queryETHHeader(ctx, fn, i)
@@ -59,6 +95,7 @@ func queryFinalizedHeader(ctx context.Context, fn getHeaderFn, height uint64, li
 			return nil, err
 		}
 		ethHeaders = append(ethHeaders, finalizedETHHeader)
+		candidate := diagnosis.addCandidate(finalizedBlock)
 
 		// child: descendant whose vote.TargetNumber == finalized.Number
 		var childList []*ETHHeader
@@ -69,11 +106,14 @@ func queryFinalizedHeader(ctx context.Context, fn getHeaderFn, height uint64, li
 			}
 			childList = append(childList, childETHHeader)
 			if childVote == nil {
+				candidate.addChild(childHeader, nil, "no vote attestation")
 				continue
 			}
 			if childVote.Data.TargetNumber != finalizedBlock.Number.Uint64() || childVote.Data.TargetHash != finalizedBlock.Hash() {
+				candidate.addChild(childHeader, childVote, "vote target is not the candidate")
 				continue
 			}
+			child := candidate.addChild(childHeader, childVote, "")
 
 			// grandChild: descendant whose vote.TargetNumber == child.Number and vote.SourceNumber == child.TargetNumber
 			var grandChildList []*ETHHeader
@@ -89,22 +129,29 @@ func queryFinalizedHeader(ctx context.Context, fn getHeaderFn, height uint64, li
 					return nil, err
 				}
 				if k-j > uint64(currentForkSpec.KAncestorGenerationDepth) {
+					child.cutOff(k, currentForkSpec.KAncestorGenerationDepth)
 					break
 				}
-
 				grandChildList = append(grandChildList, grandChildETHHeader)
 				if grandChildVote == nil {
+					child.addGrandChild(grandChildHeader, nil, "no vote attestation")
 					continue
 				}
-				if grandChildVote.Data.SourceNumber == childVote.Data.TargetNumber &&
-					grandChildVote.Data.SourceHash == childVote.Data.TargetHash &&
-					grandChildVote.Data.TargetNumber == childHeader.Number.Uint64() &&
-					grandChildVote.Data.TargetHash == childHeader.Hash() {
-					// Found headers.
-					// ELC Requires all sequential headers from the starting header
-					return append(append(ethHeaders, childList...), grandChildList...), nil
+				if grandChildVote.Data.SourceNumber != childVote.Data.TargetNumber || grandChildVote.Data.SourceHash != childVote.Data.TargetHash {
+					child.addGrandChild(grandChildHeader, grandChildVote, "vote source is not the target of the child")
+					continue
+				}
+				if grandChildVote.Data.TargetNumber != childHeader.Number.Uint64() || grandChildVote.Data.TargetHash != childHeader.Hash() {
+					child.addGrandChild(grandChildHeader, grandChildVote, "vote target is not the child")
+					continue
 				}
+				child.addGrandChild(grandChildHeader, grandChildVote, "")
+				diagnosis.finalize(finalizedBlock, childHeader, grandChildHeader)
+				// Found headers.
+				// ELC Requires all sequential headers from the starting header
+				return append(append(ethHeaders, childList...), grandChildList...), nil
 			}
+			child.unjustified()
 		}
 	}
 	log.GetLogger().DebugContext(ctx, "Insufficient verifying headers to finalize", "height", height, "limit", limitHeight)
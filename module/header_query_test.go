@@ -85,6 +85,58 @@ func (ts *HeaderQueryTestSuite) TestSuccessQueryFinalizedHeader() {
 	}
 }
 
+func (ts *HeaderQueryTestSuite) TestQueryFinalizedHeaderWithDiagnosis() {
+	fn := func(ctx context.Context, height uint64) (*types.Header, error) {
+		h := headerByHeight(int64(height))
+		if h != nil {
+			return h, nil
+		}
+		return &types.Header{Number: big.NewInt(int64(height))}, nil
+	}
+
+	for _, forkSpecs := range ts.forkSpecsPatterns {
+		diagnosis := &FinalityDiagnosis{}
+		headers, err := queryFinalizedHeaderWithDiagnosis(context.Background(), fn, 1001, 1003, forkSpecs, diagnosis)
+		ts.Require().NoError(err)
+		ts.Require().Len(headers, 3)
+		ts.Require().True(diagnosis.Finalized)
+		ts.Require().Equal(uint64(1001), diagnosis.FinalizedHeight)
+		ts.Require().Equal(uint64(1002), diagnosis.ChildHeight)
+		ts.Require().Equal(uint64(1003), diagnosis.GrandChildHeight)
+		ts.Require().Len(diagnosis.Candidates, 1)
+		child := diagnosis.Candidates[0].Children[0]
+		ts.Require().Empty(child.Failure)
+		ts.Require().Equal(uint64(1001), *child.TargetNumber)
+		ts.Require().Equal(uint64(1003), child.GrandChildren[0].Height)
+		ts.Require().Empty(child.GrandChildren[0].Failure)
+
+		// No vote attestation
+		diagnosis = &FinalityDiagnosis{}
+		headers, err = queryFinalizedHeaderWithDiagnosis(context.Background(), fn, 1, 10, forkSpecs, diagnosis)
+		ts.Require().NoError(err)
+		ts.Require().Nil(headers)
+		ts.Require().False(diagnosis.Finalized)
+		ts.Require().Len(diagnosis.Candidates, 8)
+		for _, candidate := range diagnosis.Candidates {
+			ts.Require().Len(candidate.Children, int(9-candidate.Height))
+			for _, child := range candidate.Children {
+				ts.Require().Equal("no vote attestation", child.Failure)
+				ts.Require().Nil(child.SourceNumber)
+			}
+		}
+
+		// The candidates and the children are recorded up to the limits
+		diagnosis = &FinalityDiagnosis{}
+		headers, err = queryFinalizedHeaderWithDiagnosis(context.Background(), fn, 1, 100, forkSpecs, diagnosis)
+		ts.Require().NoError(err)
+		ts.Require().Nil(headers)
+		ts.Require().Len(diagnosis.Candidates, maxDiagnosedCandidates)
+		ts.Require().Equal(uint64(98-maxDiagnosedCandidates), diagnosis.OmittedCandidates)
+		ts.Require().Len(diagnosis.Candidates[0].Children, maxDiagnosedChildren)
+		ts.Require().Equal(uint64(98-maxDiagnosedChildren), diagnosis.Candidates[0].OmittedChildren)
+	}
+}
+
 func (ts *HeaderQueryTestSuite) TestSuccessQueryLatestFinalizedHeader() {
 
 	verify := func(latestBlockNumber uint64, forkSpecs []*ForkSpec) {
@@ -95,7 +147,7 @@ func (ts *HeaderQueryTestSuite) TestSuccessQueryLatestFinalizedHeader() {
 			}
 			return &types.Header{Number: big.NewInt(int64(height))}, nil
 		}
-		height, h, err := queryLatestFinalizedHeader(context.Background(), getHeader, latestBlockNumber, forkSpecs)
+		height, h, err := queryLatestFinalizedHeader(context.Background(), getHeader, latestBlockNumber, forkSpecs, 0)
 		ts.Require().NoError(err)
 		ts.Require().Len(h, 3)
 		ts.Require().Equal(int(height), 1001)
@@ -116,7 +168,7 @@ func (ts *HeaderQueryTestSuite) TestErrorQueryLatestFinalizedHeader_NoVote() {
 				Extra:  extra,
 			}, nil
 		}
-		_, _, err := queryLatestFinalizedHeader(context.Background(), getHeader, latestBlockNumber, forkSpecs)
+		_, _, err := queryLatestFinalizedHeader(context.Background(), getHeader, latestBlockNumber, forkSpecs, 0)
 		ts.Require().True(strings.Contains(err.Error(), "no finalized header found"))
 	}
 
@@ -128,6 +180,48 @@ func (ts *HeaderQueryTestSuite) TestErrorQueryLatestFinalizedHeader_NoVote() {
 	}
 }
 
+func (ts *HeaderQueryTestSuite) TestErrorQueryLatestFinalizedHeader_MaxDepth() {
+	forkSpecs := ts.forkSpecsPatterns[0]
+	var queried []uint64
+	getHeader := func(extra []byte) getHeaderFn {
+		return func(ctx context.Context, height uint64) (*types.Header, error) {
+			queried = append(queried, height)
+			return &types.Header{Number: big.NewInt(int64(height)), Extra: extra}, nil
+		}
+	}
+
+	// No vote attestation within the depth
+	queried = nil
+	_, _, err := queryLatestFinalizedHeader(context.Background(), getHeader(nil), 100, forkSpecs, 10)
+	var notFound *FinalityNotFoundError
+	ts.Require().ErrorAs(err, &notFound)
+	ts.Require().Equal(uint64(91), notFound.LowestBlockNumber)
+	ts.Require().Nil(notFound.LastAttestation)
+	ts.Require().Len(queried, 10)
+	ts.Require().Equal(uint64(91), queried[len(queried)-1])
+
+	// The vote attestations of the same source are tried once
+	extra := common.Hex2Bytes("d88301020b846765746888676f312e32302e35856c696e7578000000b19df4a2f8b5831defffb860a44482b16993815ff4903016ce83ef788b455e2c80ba9976e8e55ac6591b9f9965234a0a2c579269bc5e09577977322d07d17bb8d657ac621a1abfadcb35b9c9d4713dbdd3d47fd3cc6dc2475c989aa224fecd083101049ef1adea2718b00e37f84c8401e5c5cfa0be938dfeafe5b932c2dcef0e2bebb1a05f31104a59b49d78b0b7746a483c14648401e5c5d0a03658f0bb6692995a9dd3b72a69ec6e8e1b9af4361718d8a275c2b92d26eeffc28027cb6d065d5a6d8749ca45a185add61b9ce470136898643170f8072513ca45f35d826f02cb2494f857beebdac9ec04196c8b30a65352ef155a28ac6a0057ff1601")
+	_, _, err = queryLatestFinalizedHeader(context.Background(), getHeader(extra), 100, forkSpecs, 10)
+	ts.Require().ErrorAs(err, &notFound)
+	ts.Require().Equal(uint64(100), notFound.LastAttestationHeight)
+	ts.Require().Equal(uint64(0x1e5c5cf), notFound.LastAttestation.SourceNumber)
+	ts.Require().Equal(1, notFound.RejectedCount)
+	ts.Require().Equal(uint64(0x1e5c5cf), notFound.Rejections[0].SourceNumber)
+	ts.Require().True(strings.Contains(err.Error(), "no finalized header found"))
+
+	// The search stops when the context is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	queried = nil
+	_, _, err = queryLatestFinalizedHeader(ctx, func(ctx context.Context, height uint64) (*types.Header, error) {
+		queried = append(queried, height)
+		cancel()
+		return &types.Header{Number: big.NewInt(int64(height))}, nil
+	}, 100, forkSpecs, 0)
+	ts.Require().ErrorIs(err, context.Canceled)
+	ts.Require().Len(queried, 1)
+}
+
 func (ts *HeaderQueryTestSuite) TestSuccessQueryFinalizedHeaderFermi() {
 	ts.Require().NoError(log.InitLogger("INFO", "json", "stdout", false))
 
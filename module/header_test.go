@@ -2,6 +2,11 @@ package module
 
 import (
 	"encoding/hex"
+	"math/big"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/suite"
 	"testing"
@@ -36,6 +41,12 @@ func (ts *HeaderTestSuite) TestNewHeaderSuccess() {
 	ts.Require().Equal(turnLength, uint8(1))
 	ts.Require().NoError(header.ValidateBasic())
 	ts.Require().Equal(header.GetHeight().GetRevisionHeight(), target.Number.Uint64())
+	ts.Require().Equal(header.GetHeight().GetRevisionNumber(), uint64(0))
+
+	// revision number is inherited from the trusted height
+	trustedHeight := clienttypes.NewHeight(2, target.Number.Uint64()-1)
+	header.TrustedHeight = &trustedHeight
+	ts.Require().Equal(header.GetHeight(), clienttypes.NewHeight(2, target.Number.Uint64()))
 }
 
 func (ts *HeaderTestSuite) TestNewHeaderError() {
@@ -45,6 +56,69 @@ func (ts *HeaderTestSuite) TestNewHeaderError() {
 	ts.Require().Error(header.ValidateBasic())
 }
 
+func (ts *HeaderTestSuite) TestMalformedHeader() {
+	header := Header{
+		Headers: []*ETHHeader{{Header: []byte{0x01, 0x02}}},
+	}
+	ts.Require().NotPanics(func() {
+		ts.Require().Equal(header.GetHeight(), clienttypes.ZeroHeight())
+	})
+	_, err := header.Height()
+	ts.Require().Error(err)
+	ts.Require().Error(header.ValidateBasic())
+}
+
+func (ts *HeaderTestSuite) TestValidateBasic() {
+	newHeaders := func(numbers ...int64) []*ETHHeader {
+		var headers []*ETHHeader
+		parentHash := common.Hash{}
+		for _, n := range numbers {
+			h := &types.Header{Number: big.NewInt(n), ParentHash: parentHash, Difficulty: big.NewInt(2)}
+			ethHeader, err := newETHHeader(h)
+			ts.Require().NoError(err)
+			headers = append(headers, ethHeader)
+			parentHash = h.Hash()
+		}
+		return headers
+	}
+
+	header := Header{Headers: newHeaders(10, 11, 12)}
+	ts.Require().NoError(header.ValidateBasic())
+	height, err := header.Height()
+	ts.Require().NoError(err)
+	ts.Require().Equal(height, clienttypes.NewHeight(0, 10))
+
+	// roots and hashes must be 32 bytes
+	for index, name := range map[int]string{0: "parent hash", 3: "state root", 5: "receipts root"} {
+		var fields []rlp.RawValue
+		ts.Require().NoError(rlp.DecodeBytes(newHeaders(10)[0].Header, &fields))
+		fields[index], err = rlp.EncodeToBytes(make([]byte, common.HashLength-1))
+		ts.Require().NoError(err)
+		raw, err := rlp.EncodeToBytes(fields)
+		ts.Require().NoError(err)
+		header = Header{Headers: []*ETHHeader{{Header: raw}}}
+		ts.Require().ErrorContains(header.ValidateBasic(), "invalid "+name+" length", name)
+	}
+
+	// not contiguous
+	header = Header{Headers: newHeaders(10, 12)}
+	ts.Require().ErrorContains(header.ValidateBasic(), "not contiguous")
+
+	// parent hash mismatch
+	header = Header{Headers: append(newHeaders(10), newHeaders(11)...)}
+	ts.Require().ErrorContains(header.ValidateBasic(), "unexpected parent hash")
+
+	// invalid validator size
+	header = Header{Headers: newHeaders(10), CurrentValidators: [][]byte{make([]byte, 20)}}
+	ts.Require().ErrorContains(header.ValidateBasic(), "invalid current validators")
+	header = Header{Headers: newHeaders(10), PreviousValidators: [][]byte{make([]byte, validatorBytesLength)}}
+	ts.Require().NoError(header.ValidateBasic())
+
+	// too many headers
+	header = Header{Headers: make([]*ETHHeader, maxHeadersLength+1)}
+	ts.Require().ErrorContains(header.ValidateBasic(), "too many headers")
+}
+
 // see yui-ibc-solidity
 func encodeRLP(proof []string) ([]byte, error) {
 	var target [][][]byte
@@ -0,0 +1,151 @@
+package module
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/gogoproto/proto"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// ClientHealth is the health of the Parlia client on the counterparty chain.
+type ClientHealth struct {
+	ClientID        string
+	LatestHeight    clienttypes.Height
+	LatestTimestamp time.Time
+	Frozen          bool
+	// ChainHeight and ChainTimestamp are the latest block of the self chain
+	ChainHeight    uint64
+	ChainTimestamp time.Time
+	// LagBlocks and LagTime are how far the client lags behind the self chain
+	LagBlocks uint64
+	LagTime   time.Duration
+	// TrustingPeriodLeft is measured at ChainTimestamp and negative if the client has expired
+	TrustingPeriod     time.Duration
+	TrustingPeriodLeft time.Duration
+	// The validator hashes of the latest consensus state are compared with the epoch validator sets of the self chain
+	CurrentValidatorsHashMatch  bool
+	PreviousValidatorsHashMatch bool
+	// ForkSpecsCurrent is true if the fork specs of the client are the same as the configured network
+	ForkSpecsCurrent bool
+	// IbcStoreAddressMatch is true if the IBC store address of the client is the configured IBC contract
+	IbcStoreAddressMatch bool
+}
+
+// Healthy returns true if the client can be updated and its states match the self chain.
+func (h *ClientHealth) Healthy() bool {
+	return !h.Frozen && !h.Expired() &&
+		h.CurrentValidatorsHashMatch && h.PreviousValidatorsHashMatch &&
+		h.ForkSpecsCurrent && h.IbcStoreAddressMatch
+}
+
+// Expired returns true if the trusting period of the latest consensus state has passed.
+func (h *ClientHealth) Expired() bool {
+	return h.TrustingPeriod > 0 && h.TrustingPeriodLeft <= 0
+}
+
+func (h *ClientHealth) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ClientID                    string             `json:"client_id"`
+		Healthy                     bool               `json:"healthy"`
+		LatestHeight                clienttypes.Height `json:"latest_height"`
+		LatestTimestamp             time.Time          `json:"latest_timestamp"`
+		Frozen                      bool               `json:"frozen"`
+		ChainHeight                 uint64             `json:"chain_height"`
+		ChainTimestamp              time.Time          `json:"chain_timestamp"`
+		LagBlocks                   uint64             `json:"lag_blocks"`
+		LagTime                     string             `json:"lag_time"`
+		TrustingPeriod              string             `json:"trusting_period"`
+		TrustingPeriodLeft          string             `json:"trusting_period_left"`
+		Expired                     bool               `json:"expired"`
+		CurrentValidatorsHashMatch  bool               `json:"current_validators_hash_match"`
+		PreviousValidatorsHashMatch bool               `json:"previous_validators_hash_match"`
+		ForkSpecsCurrent            bool               `json:"fork_specs_current"`
+		IbcStoreAddressMatch        bool               `json:"ibc_store_address_match"`
+	}{
+		ClientID:                    h.ClientID,
+		Healthy:                     h.Healthy(),
+		LatestHeight:                h.LatestHeight,
+		LatestTimestamp:             h.LatestTimestamp,
+		Frozen:                      h.Frozen,
+		ChainHeight:                 h.ChainHeight,
+		ChainTimestamp:              h.ChainTimestamp,
+		LagBlocks:                   h.LagBlocks,
+		LagTime:                     h.LagTime.String(),
+		TrustingPeriod:              h.TrustingPeriod.String(),
+		TrustingPeriodLeft:          h.TrustingPeriodLeft.String(),
+		Expired:                     h.Expired(),
+		CurrentValidatorsHashMatch:  h.CurrentValidatorsHashMatch,
+		PreviousValidatorsHashMatch: h.PreviousValidatorsHashMatch,
+		ForkSpecsCurrent:            h.ForkSpecsCurrent,
+		IbcStoreAddressMatch:        h.IbcStoreAddressMatch,
+	})
+}
+
+// CheckClientHealth reports the health of the Parlia client on the counterparty chain.
+func (pr *Prover) CheckClientHealth(ctx context.Context, counterparty core.Chain) (*ClientHealth, error) {
+	_, exportedCs, exportedCons, err := pr.queryCounterpartyClient(ctx, counterparty)
+	if err != nil {
+		return nil, err
+	}
+	cs, ok := exportedCs.(*ClientState)
+	if !ok {
+		return nil, fmt.Errorf("unexpected client state type: %T", exportedCs)
+	}
+	cons, ok := exportedCons.(*ConsensusState)
+	if !ok {
+		return nil, fmt.Errorf("unexpected consensus state type: %T", exportedCons)
+	}
+
+	selfHeight, err := pr.chain.LatestHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the latest height of the self chain: %+v", err)
+	}
+	selfTimestamp, err := pr.chain.Timestamp(ctx, selfHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timestamp of the self chain: %+v", err)
+	}
+	latestTimestamp := consensusStateTime(cons)
+	trustingPeriod, _ := pr.trustingParameters(cs)
+
+	health := &ClientHealth{
+		ClientID:             counterparty.Path().ClientID,
+		LatestHeight:         *cs.LatestHeight,
+		LatestTimestamp:      latestTimestamp,
+		Frozen:               cs.Frozen,
+		ChainHeight:          selfHeight.GetRevisionHeight(),
+		ChainTimestamp:       selfTimestamp,
+		LagTime:              selfTimestamp.Sub(latestTimestamp),
+		TrustingPeriod:       trustingPeriod,
+		TrustingPeriodLeft:   latestTimestamp.Add(trustingPeriod).Sub(selfTimestamp),
+		ForkSpecsCurrent:     forkSpecsEqual(cs.ForkSpecs, pr.getForkParameters()),
+		IbcStoreAddressMatch: bytes.Equal(cs.IbcStoreAddress, pr.chain.IBCAddress().Bytes()),
+	}
+	if health.ChainHeight > health.LatestHeight.RevisionHeight {
+		health.LagBlocks = health.ChainHeight - health.LatestHeight.RevisionHeight
+	}
+
+	epoch, err := queryEpochValidators(ctx, pr.chain.Header, cs.GetLatestHeight().GetRevisionHeight(), pr.getForkParameters())
+	if err != nil {
+		return nil, err
+	}
+	health.CurrentValidatorsHashMatch = bytes.Equal(cons.CurrentValidatorsHash, makeEpochHash(epoch.CurrentValidators, epoch.CurrentTurnLength))
+	health.PreviousValidatorsHashMatch = bytes.Equal(cons.PreviousValidatorsHash, makeEpochHash(epoch.PreviousValidators, epoch.PreviousTurnLength))
+	return health, nil
+}
+
+func forkSpecsEqual(x, y []*ForkSpec) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if !proto.Equal(x[i], y[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package module
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthTestSuite struct {
+	suite.Suite
+}
+
+func TestHealthTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthTestSuite))
+}
+
+func (ts *HealthTestSuite) healthy() *ClientHealth {
+	return &ClientHealth{
+		TrustingPeriod:              time.Hour,
+		TrustingPeriodLeft:          time.Minute,
+		CurrentValidatorsHashMatch:  true,
+		PreviousValidatorsHashMatch: true,
+		ForkSpecsCurrent:            true,
+		IbcStoreAddressMatch:        true,
+	}
+}
+
+func (ts *HealthTestSuite) TestHealthy() {
+	ts.Require().True(ts.healthy().Healthy())
+
+	expired := ts.healthy()
+	expired.TrustingPeriodLeft = -time.Second
+	ts.Require().True(expired.Expired())
+	ts.Require().False(expired.Healthy())
+
+	// zero trusting period never expires
+	noExpiry := ts.healthy()
+	noExpiry.TrustingPeriod = 0
+	noExpiry.TrustingPeriodLeft = -time.Second
+	ts.Require().True(noExpiry.Healthy())
+
+	frozen := ts.healthy()
+	frozen.Frozen = true
+	ts.Require().False(frozen.Healthy())
+
+	validators := ts.healthy()
+	validators.PreviousValidatorsHashMatch = false
+	ts.Require().False(validators.Healthy())
+
+	forkSpecs := ts.healthy()
+	forkSpecs.ForkSpecsCurrent = false
+	ts.Require().False(forkSpecs.Healthy())
+
+	address := ts.healthy()
+	address.IbcStoreAddressMatch = false
+	ts.Require().False(address.Healthy())
+}
+
+func (ts *HealthTestSuite) TestMarshalJSON() {
+	health := ts.healthy()
+	health.LagTime = 90 * time.Second
+	bz, err := json.Marshal(health)
+	ts.Require().NoError(err)
+	var result map[string]interface{}
+	ts.Require().NoError(json.Unmarshal(bz, &result))
+	ts.Require().Equal(true, result["healthy"])
+	ts.Require().Equal("1m30s", result["lag_time"])
+	ts.Require().Equal("1m0s", result["trusting_period_left"])
+}
+
+func (ts *HealthTestSuite) TestForkSpecsEqual() {
+	ts.Require().True(forkSpecsEqual(GetForkParameters(Localnet), GetForkParameters(Localnet)))
+	ts.Require().False(forkSpecsEqual(GetForkParameters(Localnet), GetForkParameters(Localnet)[1:]))
+	modified := GetForkParameters(Localnet)
+	modified[0] = &ForkSpec{HeightOrTimestamp: &ForkSpec_Height{Height: 1}, EpochLength: 1}
+	ts.Require().False(forkSpecsEqual(modified, GetForkParameters(Localnet)))
+}
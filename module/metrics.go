@@ -0,0 +1,39 @@
+package module
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	meter = otel.Meter("github.com/datachainlab/ibc-parlia-relay/module")
+
+	// fastFinalityErrorCounter counts the intermediate headers whose finalized header is not found in the window.
+	// The `result` attribute is recovered, partial or failed.
+	fastFinalityErrorCounter metric.Int64Counter
+
+	// finalityNotFoundCounter counts the searches of the latest finalized header which found nothing within the search depth.
+	finalityNotFoundCounter metric.Int64Counter
+
+	// finalizedTagDivergenceCounter counts the finalized heights reported by the node that diverge from the derived ones beyond the tolerance.
+	finalizedTagDivergenceCounter metric.Int64Counter
+)
+
+func init() {
+	var err error
+	fastFinalityErrorCounter, err = meter.Int64Counter("parlia.fast_finality_error",
+		metric.WithDescription("number of the intermediate headers whose finalized header is not found in the search window"))
+	if err != nil {
+		panic(err)
+	}
+	finalityNotFoundCounter, err = meter.Int64Counter("parlia.finality_not_found",
+		metric.WithDescription("number of the searches of the latest finalized header which found nothing within the search depth"))
+	if err != nil {
+		panic(err)
+	}
+	finalizedTagDivergenceCounter, err = meter.Int64Counter("parlia.finalized_tag_divergence",
+		metric.WithDescription("number of the finalized heights reported by the node that diverge from the derived ones beyond the tolerance"))
+	if err != nil {
+		panic(err)
+	}
+}
@@ -1,5 +1,12 @@
 package module
 
+import (
+	"context"
+	"fmt"
+
+	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/client"
+)
+
 func (*Misbehaviour) ClientType() string {
 	return Parlia
 }
@@ -17,3 +24,78 @@ func (h *Misbehaviour) ValidateBasic() error {
 	}
 	return nil
 }
+
+// BuildMisbehaviour returns a Misbehaviour of two conflicting headers after validating it locally.
+func BuildMisbehaviour(clientID string, header1, header2 *Header) (*Misbehaviour, error) {
+	misbehaviour := &Misbehaviour{
+		ClientId: clientID,
+		Header_1: header1,
+		Header_2: header2,
+	}
+	if err := misbehaviour.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	for i, h := range []*Header{header1, header2} {
+		if h.TrustedHeight == nil {
+			return nil, fmt.Errorf("trusted height of header %d is empty", i+1)
+		}
+		if !h.TrustedHeight.LT(h.GetHeight()) {
+			return nil, fmt.Errorf("trusted height %s of header %d must be less than the height %s", h.TrustedHeight, i+1, h.GetHeight())
+		}
+	}
+	if !header1.GetHeight().EQ(header2.GetHeight()) {
+		return nil, fmt.Errorf("headers must have the same height: %s, %s", header1.GetHeight(), header2.GetHeight())
+	}
+	target1, err := header1.Target()
+	if err != nil {
+		return nil, err
+	}
+	target2, err := header2.Target()
+	if err != nil {
+		return nil, err
+	}
+	if target1.Hash() == target2.Hash() {
+		return nil, fmt.Errorf("headers are identical: %s", target1.Hash())
+	}
+	return misbehaviour, nil
+}
+
+// QueryMisbehaviourHeader returns the verifiable header finalized at `height` on the chain of `rpcAddr`.
+// It fails if the counterparty client needs intermediate headers to be updated from `trustedHeight` to `height`.
+func QueryMisbehaviourHeader(ctx context.Context, rpcAddr string, config *ProverConfig, height uint64, trustedHeight uint64) (*Header, error) {
+	cl, err := client.NewETHClient(rpcAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %+v", rpcAddr, err)
+	}
+	chain, err := newRPCChain(ctx, cl)
+	if err != nil {
+		return nil, err
+	}
+	pr := &Prover{chain: chain, config: config}
+	latestHeight, err := pr.chain.LatestHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ethHeaders, err := queryFinalizedHeader(ctx, pr.chain.Header, height, latestHeight.GetRevisionHeight(), pr.getForkParameters())
+	if err != nil {
+		return nil, err
+	}
+	if ethHeaders == nil {
+		return nil, fmt.Errorf("header is not finalized yet: rpc=%s, height=%d", rpcAddr, height)
+	}
+	verifiable, err := pr.withValidators(ctx, height, ethHeaders)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := pr.SetupHeadersForUpdateByLatestHeight(ctx, pr.newHeight(trustedHeight), verifiable.(*Header))
+	if err != nil {
+		return nil, err
+	}
+	if len(headers) == 0 {
+		return nil, fmt.Errorf("no header to submit: rpc=%s, height=%d, trusted=%d", rpcAddr, height, trustedHeight)
+	}
+	if len(headers) != 1 {
+		return nil, fmt.Errorf("the client must be updated to the intermediate height %s before submitting misbehaviour at %d", headers[0].GetHeight(), height)
+	}
+	return headers[0].(*Header), nil
+}
@@ -0,0 +1,189 @@
+package module
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/hyperledger-labs/yui-relayer/config"
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"github.com/stretchr/testify/suite"
+)
+
+// rpcBackend serves the blocks through the JSON-RPC of the Ethereum API.
+type rpcBackend struct {
+	headers []*types.Header
+}
+
+func (b *rpcBackend) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(9999))
+}
+
+func (b *rpcBackend) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(len(b.headers) - 1)
+}
+
+func (b *rpcBackend) GetBlockByNumber(number rpc.BlockNumber, _ bool) (map[string]interface{}, error) {
+	if number < 0 || int(number) >= len(b.headers) {
+		return nil, nil
+	}
+	bz, err := json.Marshal(b.headers[number])
+	if err != nil {
+		return nil, err
+	}
+	var block map[string]interface{}
+	if err = json.Unmarshal(bz, &block); err != nil {
+		return nil, err
+	}
+	block["transactions"] = []interface{}{}
+	block["uncles"] = []interface{}{}
+	return block, nil
+}
+
+type MisbehaviourTestSuite struct {
+	suite.Suite
+}
+
+func TestMisbehaviourTestSuite(t *testing.T) {
+	suite.Run(t, new(MisbehaviourTestSuite))
+}
+
+func (ts *MisbehaviourTestSuite) SetupTest() {
+	err := log.InitLogger("DEBUG", "text", "stdout", false)
+	ts.Require().NoError(err)
+}
+
+// serveRPC starts the RPC server of the localnet chain up to 2003, where 2001 is finalized by the votes in 2002 and 2003.
+func (ts *MisbehaviourTestSuite) serveRPC() string {
+	epochExtra := epochHeader().Extra
+	votes := map[uint64]uint64{2002: 2001, 2003: 2002}
+	backend := &rpcBackend{}
+	for number := uint64(0); number <= 2003; number++ {
+		h := &types.Header{
+			Number:      big.NewInt(int64(number)),
+			Difficulty:  big.NewInt(2),
+			UncleHash:   types.EmptyUncleHash,
+			TxHash:      types.EmptyTxsHash,
+			ReceiptHash: types.EmptyReceiptsHash,
+			Extra:       epochExtra,
+		}
+		if number > 0 {
+			h.ParentHash = backend.headers[number-1].Hash()
+		}
+		if target, ok := votes[number]; ok {
+			attestation, err := rlp.EncodeToBytes(&VoteAttestation{
+				VoteAddressSet: 1,
+				Data: &VoteData{
+					SourceNumber: target - 1,
+					SourceHash:   backend.headers[target-1].Hash(),
+					TargetNumber: target,
+					TargetHash:   backend.headers[target].Hash(),
+				},
+			})
+			ts.Require().NoError(err)
+			h.Extra = append(append(make([]byte, extraVanity), attestation...), make([]byte, extraSeal)...)
+		}
+		backend.headers = append(backend.headers, h)
+	}
+	server := rpc.NewServer()
+	ts.Require().NoError(server.RegisterName("eth", backend))
+	httpServer := httptest.NewServer(server)
+	ts.T().Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return httpServer.URL
+}
+
+func (ts *MisbehaviourTestSuite) header(number int64, root common.Hash, trusted uint64) *Header {
+	ethHeader, err := newETHHeader(&types.Header{Number: big.NewInt(number), Root: root, Difficulty: big.NewInt(2)})
+	ts.Require().NoError(err)
+	trustedHeight := clienttypes.NewHeight(0, trusted)
+	return &Header{Headers: []*ETHHeader{ethHeader}, TrustedHeight: &trustedHeight}
+}
+
+func (ts *MisbehaviourTestSuite) TestBuildMisbehaviour() {
+	header1 := ts.header(100, common.HexToHash("0x01"), 90)
+	header2 := ts.header(100, common.HexToHash("0x02"), 95)
+	misbehaviour, err := BuildMisbehaviour("xx-parlia-1", header1, header2)
+	ts.Require().NoError(err)
+	ts.Require().Equal("xx-parlia-1", misbehaviour.GetClientID())
+	ts.Require().Equal(header1, misbehaviour.Header_1)
+	ts.Require().Equal(header2, misbehaviour.Header_2)
+
+	// identical headers
+	_, err = BuildMisbehaviour("xx-parlia-1", header1, ts.header(100, common.HexToHash("0x01"), 95))
+	ts.Require().ErrorContains(err, "identical")
+
+	// different heights
+	_, err = BuildMisbehaviour("xx-parlia-1", header1, ts.header(101, common.HexToHash("0x02"), 95))
+	ts.Require().ErrorContains(err, "same height")
+
+	// trusted height is not less than the height
+	_, err = BuildMisbehaviour("xx-parlia-1", header1, ts.header(100, common.HexToHash("0x02"), 100))
+	ts.Require().ErrorContains(err, "trusted height")
+
+	// empty trusted height
+	header2.TrustedHeight = nil
+	_, err = BuildMisbehaviour("xx-parlia-1", header1, header2)
+	ts.Require().ErrorContains(err, "trusted height of header 2 is empty")
+}
+
+func (ts *MisbehaviourTestSuite) TestQueryMisbehaviourHeader() {
+	ctx := context.Background()
+	rpcAddr := ts.serveRPC()
+	config := &ProverConfig{Network: string(Localnet), RevisionNumber: 1}
+
+	header, err := QueryMisbehaviourHeader(ctx, rpcAddr, config, 2001, 2000)
+	ts.Require().NoError(err)
+	ts.Require().Equal(clienttypes.NewHeight(1, 2001), header.GetHeight())
+	ts.Require().Equal(clienttypes.NewHeight(1, 2000), *header.TrustedHeight)
+	ts.Require().Len(header.Headers, 3)
+
+	// the header is not finalized yet
+	_, err = QueryMisbehaviourHeader(ctx, rpcAddr, config, 2002, 2000)
+	ts.Require().ErrorContains(err, "not finalized yet")
+
+	// no finalized header is found for the intermediate height
+	config.SubmissionInterval = 5
+	_, err = QueryMisbehaviourHeader(ctx, rpcAddr, config, 2001, 1990)
+	ts.Require().ErrorIs(err, ErrInsufficientVoteAttestation)
+}
+
+func (ts *MisbehaviourTestSuite) TestExportHeader() {
+	registry := codectypes.NewInterfaceRegistry()
+	clienttypes.RegisterInterfaces(registry)
+	Module{}.RegisterInterfaces(registry)
+	execute := func(args ...string) (*Header, error) {
+		cmd := exportHeaderCmd(&config.Context{Codec: codec.NewProtoCodec(registry)})
+		out := new(bytes.Buffer)
+		cmd.SetOut(out)
+		cmd.SetArgs(args)
+		if err := cmd.ExecuteContext(context.Background()); err != nil {
+			return nil, err
+		}
+		var header Header
+		ts.Require().NoError(codec.NewProtoCodec(registry).UnmarshalJSON(out.Bytes(), &header))
+		return &header, nil
+	}
+	rpcAddr := ts.serveRPC()
+
+	header, err := execute(rpcAddr, "--height", "2001", "--trusted-height", "2000", "--network", string(Localnet))
+	ts.Require().NoError(err)
+	ts.Require().Equal(uint64(2001), header.GetHeight().GetRevisionHeight())
+
+	// the header is not finalized yet
+	_, err = execute(rpcAddr, "--height", "2002", "--trusted-height", "2000", "--network", string(Localnet))
+	ts.Require().ErrorContains(err, "not finalized yet")
+}
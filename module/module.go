@@ -26,6 +26,7 @@ func (Module) RegisterInterfaces(registry codectypes.InterfaceRegistry) {
 	registry.RegisterImplementations(
 		(*exported.ClientMessage)(nil),
 		&Header{},
+		&Misbehaviour{},
 	)
 	registry.RegisterImplementations(
 		(*exported.ClientState)(nil),
@@ -39,5 +40,5 @@ func (Module) RegisterInterfaces(registry codectypes.InterfaceRegistry) {
 
 // GetCmd returns the command
 func (Module) GetCmd(ctx *config.Context) *cobra.Command {
-	return nil
+	return parliaCmd(ctx)
 }
@@ -1,10 +1,18 @@
 package module
 
 import (
+	"bytes"
+	"fmt"
+	"math"
+	"time"
+
 	storetypes "cosmossdk.io/store/types"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
 	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 var _ exported.ClientState = (*ClientState)(nil)
@@ -17,7 +25,56 @@ func (cs *ClientState) GetLatestHeight() exported.Height {
 	return *cs.LatestHeight
 }
 
+// Validate performs a basic validation of the client state parameters.
 func (cs *ClientState) Validate() error {
+	if cs.ChainId == 0 {
+		return fmt.Errorf("chain id cannot be zero")
+	}
+	if len(cs.IbcStoreAddress) != common.AddressLength {
+		return fmt.Errorf("invalid ibc store address length: %d", len(cs.IbcStoreAddress))
+	}
+	if len(cs.IbcCommitmentsSlot) != common.HashLength {
+		return fmt.Errorf("invalid ibc commitments slot length: %d", len(cs.IbcCommitmentsSlot))
+	}
+	if cs.LatestHeight == nil || cs.LatestHeight.IsZero() {
+		return fmt.Errorf("latest height cannot be zero")
+	}
+	if cs.TrustingPeriod <= cs.MaxClockDrift {
+		return fmt.Errorf("trusting period must be greater than max clock drift: trustingPeriod=%s, maxClockDrift=%s", cs.TrustingPeriod, cs.MaxClockDrift)
+	}
+	return validateForkSpecs(cs.ForkSpecs)
+}
+
+// validateForkSpecs checks that the fork specs are not empty, have sane parameters and are sorted in ascending order.
+func validateForkSpecs(forkSpecs []*ForkSpec) error {
+	if len(forkSpecs) == 0 {
+		return fmt.Errorf("fork specs cannot be empty")
+	}
+	for i, spec := range forkSpecs {
+		if spec == nil || spec.GetHeightOrTimestamp() == nil {
+			return fmt.Errorf("fork spec has neither height nor timestamp: index=%d", i)
+		}
+		if spec.EpochLength == 0 {
+			return fmt.Errorf("epoch length cannot be zero: index=%d", i)
+		}
+		if spec.MaxTurnLength == 0 || spec.MaxTurnLength > math.MaxUint8 || spec.MaxTurnLength > spec.EpochLength {
+			return fmt.Errorf("invalid max turn length: index=%d, maxTurnLength=%d, epochLength=%d", i, spec.MaxTurnLength, spec.EpochLength)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := forkSpecs[i-1]
+		switch condition := spec.GetHeightOrTimestamp().(type) {
+		case *ForkSpec_Height:
+			if x, ok := prev.GetHeightOrTimestamp().(*ForkSpec_Height); ok && x.Height >= condition.Height {
+				return fmt.Errorf("fork specs are not sorted by height: index=%d, prev=%d, current=%d", i, x.Height, condition.Height)
+			}
+		case *ForkSpec_Timestamp:
+			if x, ok := prev.GetHeightOrTimestamp().(*ForkSpec_Timestamp); ok && x.Timestamp >= condition.Timestamp {
+				return fmt.Errorf("fork specs are not sorted by timestamp: index=%d, prev=%d, current=%d", i, x.Timestamp, condition.Timestamp)
+			}
+		}
+	}
 	return nil
 }
 
@@ -29,8 +86,15 @@ func (cs *ClientState) ExportMetadata(clientStore storetypes.KVStore) []exported
 	panic("not implemented")
 }
 
+// ZeroCustomFields returns a copy of the client state with the client specific parameters zeroed out.
 func (cs *ClientState) ZeroCustomFields() exported.ClientState {
-	panic("not implemented")
+	return &ClientState{
+		ChainId:            cs.ChainId,
+		IbcStoreAddress:    cs.IbcStoreAddress,
+		IbcCommitmentsSlot: cs.IbcCommitmentsSlot,
+		LatestHeight:       cs.LatestHeight,
+		ForkSpecs:          cs.ForkSpecs,
+	}
 }
 
 func (cs *ClientState) GetTimestampAtHeight(
@@ -89,20 +153,125 @@ func (cs *ClientState) UpdateState(ctx sdk.Context, cdc codec.BinaryCodec, clien
 	panic("not implemented")
 }
 
-func (cs *ClientState) CheckSubstituteAndUpdateState(ctx sdk.Context, cdc codec.BinaryCodec, subjectClientStore, substituteClientStore storetypes.KVStore, substituteCliente exported.ClientState) error {
-	panic("not implemented")
+// CheckSubstituteAndUpdateState replaces the subject client with the latest state of the substitute client.
+// The substitute must track the same chain and IBC contract as the subject.
+// A frozen subject client is unfrozen by this operation.
+func (cs *ClientState) CheckSubstituteAndUpdateState(ctx sdk.Context, cdc codec.BinaryCodec, subjectClientStore, substituteClientStore storetypes.KVStore, substituteClient exported.ClientState) error {
+	substituteClientState, ok := substituteClient.(*ClientState)
+	if !ok {
+		return fmt.Errorf("%w: expected type %T, got %T", clienttypes.ErrInvalidClient, &ClientState{}, substituteClient)
+	}
+	if !IsMatchingClientState(cs, substituteClientState) {
+		return fmt.Errorf("%w: subject client state does not match substitute client state", clienttypes.ErrInvalidSubstitute)
+	}
+	if substituteClientState.LatestHeight == nil {
+		return fmt.Errorf("%w: substitute client has no latest height", clienttypes.ErrInvalidSubstitute)
+	}
+
+	height := substituteClientState.GetLatestHeight()
+	consensusState, found := getConsensusState(substituteClientStore, cdc, height)
+	if !found {
+		return fmt.Errorf("%w: unable to retrieve latest consensus state for substitute client", clienttypes.ErrConsensusStateNotFound)
+	}
+	setConsensusState(subjectClientStore, cdc, consensusState, height)
+
+	newClientState := *cs
+	newClientState.Frozen = false
+	newClientState.LatestHeight = substituteClientState.LatestHeight
+	newClientState.TrustingPeriod = substituteClientState.TrustingPeriod
+	newClientState.MaxClockDrift = substituteClientState.MaxClockDrift
+	newClientState.ForkSpecs = substituteClientState.ForkSpecs
+	setClientState(subjectClientStore, cdc, &newClientState)
+	return nil
+}
+
+// IsMatchingClientState returns true if both client states track the same chain and IBC contract.
+// The latest height, frozen flag, trusting period, max clock drift and fork specs are allowed to differ.
+func IsMatchingClientState(subject, substitute *ClientState) bool {
+	return subject.ChainId == substitute.ChainId &&
+		bytes.Equal(subject.IbcStoreAddress, substitute.IbcStoreAddress) &&
+		bytes.Equal(subject.IbcCommitmentsSlot, substitute.IbcCommitmentsSlot)
 }
 
+// VerifyUpgradeAndUpdateState verifies that the upgraded client and consensus states were committed by the IBC contract
+// at the latest height of the client, and replaces the client with them.
+// The trusting period and max clock drift of the current client are retained.
 func (cs *ClientState) VerifyUpgradeAndUpdateState(
 	ctx sdk.Context,
 	cdc codec.BinaryCodec,
-	store storetypes.KVStore,
+	clientStore storetypes.KVStore,
 	newClient exported.ClientState,
 	newConsState exported.ConsensusState,
 	proofUpgradeClient,
 	proofUpgradeConsState []byte,
 ) error {
-	panic("not implemented")
+	lastHeight := cs.GetLatestHeight()
+	if !newClient.GetLatestHeight().GT(lastHeight) {
+		return fmt.Errorf("%w: upgraded client height %s must be greater than current client height %s", clienttypes.ErrInvalidUpgradeClient, newClient.GetLatestHeight(), lastHeight)
+	}
+	upgradedClientState, ok := newClient.(*ClientState)
+	if !ok {
+		return fmt.Errorf("%w: upgraded client must be Parlia client. expected: %T got: %T", clienttypes.ErrInvalidClientType, &ClientState{}, newClient)
+	}
+	upgradedConsState, ok := newConsState.(*ConsensusState)
+	if !ok {
+		return fmt.Errorf("%w: upgraded consensus state must be Parlia consensus state. expected: %T got: %T", clienttypes.ErrInvalidConsensus, &ConsensusState{}, newConsState)
+	}
+	if !bytes.Equal(cs.IbcStoreAddress, upgradedClientState.IbcStoreAddress) || !bytes.Equal(cs.IbcCommitmentsSlot, upgradedClientState.IbcCommitmentsSlot) {
+		return fmt.Errorf("%w: upgraded client must keep the IBC store address and commitments slot", clienttypes.ErrInvalidUpgradeClient)
+	}
+
+	// The upgraded states must be committed by the IBC contract at the latest trusted state
+	consState, found := getConsensusState(clientStore, cdc, lastHeight)
+	if !found {
+		return fmt.Errorf("%w: could not retrieve consensus state for lastHeight", clienttypes.ErrConsensusStateNotFound)
+	}
+	bzClient, err := cdc.MarshalInterface(upgradedClientState.ZeroCustomFields())
+	if err != nil {
+		return fmt.Errorf("%w: could not marshal client state: %v", clienttypes.ErrInvalidClient, err)
+	}
+	if err = cs.verifyStateCommitment(consState, proofUpgradeClient, UpgradedClientPath(lastHeight.GetRevisionHeight()), bzClient); err != nil {
+		return fmt.Errorf("%w: client state proof failed: %v", clienttypes.ErrInvalidUpgradeClient, err)
+	}
+	bzConsState, err := cdc.MarshalInterface(upgradedConsState)
+	if err != nil {
+		return fmt.Errorf("%w: could not marshal consensus state: %v", clienttypes.ErrInvalidConsensus, err)
+	}
+	if err = cs.verifyStateCommitment(consState, proofUpgradeConsState, UpgradedConsStatePath(lastHeight.GetRevisionHeight()), bzConsState); err != nil {
+		return fmt.Errorf("%w: consensus state proof failed: %v", clienttypes.ErrInvalidUpgradeClient, err)
+	}
+
+	// Client specific parameters are retained from the current client
+	newClientState := *upgradedClientState
+	newClientState.TrustingPeriod = cs.TrustingPeriod
+	newClientState.MaxClockDrift = cs.MaxClockDrift
+	newClientState.Frozen = false
+	setClientState(clientStore, cdc, &newClientState)
+	setConsensusState(clientStore, cdc, upgradedConsState, newClientState.GetLatestHeight())
+	return nil
+}
+
+// verifyStateCommitment verifies that the IBC contract had committed keccak256(value) at the path.
+func (cs *ClientState) verifyStateCommitment(consState *ConsensusState, proof []byte, path string, value []byte) error {
+	var proveState ProveState
+	if err := proveState.Unmarshal(proof); err != nil {
+		return fmt.Errorf("failed to unmarshal proof: %v", err)
+	}
+	storageRoot, err := verifyStorageRoot(common.BytesToHash(consState.StateRoot), proveState.AccountProof, common.BytesToAddress(cs.IbcStoreAddress))
+	if err != nil {
+		return err
+	}
+	return verifyCommitment(storageRoot, proveState.CommitmentProof, common.BytesToHash(cs.IbcCommitmentsSlot), path, crypto.Keccak256(value))
+}
+
+// UpgradedClientPath returns the commitment path of the upgraded client state planned at the height.
+func UpgradedClientPath(height uint64) string {
+	return fmt.Sprintf("upgradedIBCState/%d/upgradedClient", height)
+}
+
+// UpgradedConsStatePath returns the commitment path of the upgraded consensus state planned at the height.
+func UpgradedConsStatePath(height uint64) string {
+	return fmt.Sprintf("upgradedIBCState/%d/upgradedConsState", height)
 }
 
 var _ exported.ConsensusState = (*ConsensusState)(nil)
@@ -116,7 +285,21 @@ func (cs *ConsensusState) GetTimestamp() uint64 {
 	return cs.Timestamp
 }
 
-// ValidateBasic defines basic validation for the mock-client consensus state.
+// consensusStateTime returns the time of the consensus state, whose timestamp is the block time in milliseconds.
+func consensusStateTime(cons exported.ConsensusState) time.Time {
+	return time.UnixMilli(int64(cons.GetTimestamp()))
+}
+
+// ValidateBasic checks the length of the state root and the validator set hashes.
 func (cs *ConsensusState) ValidateBasic() error {
+	if len(cs.StateRoot) != common.HashLength {
+		return fmt.Errorf("invalid state root length: %d", len(cs.StateRoot))
+	}
+	if len(cs.CurrentValidatorsHash) != common.HashLength {
+		return fmt.Errorf("invalid current validators hash length: %d", len(cs.CurrentValidatorsHash))
+	}
+	if len(cs.PreviousValidatorsHash) != common.HashLength {
+		return fmt.Errorf("invalid previous validators hash length: %d", len(cs.PreviousValidatorsHash))
+	}
 	return nil
 }
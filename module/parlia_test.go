@@ -0,0 +1,260 @@
+package module
+
+import (
+	"testing"
+	"time"
+
+	"cosmossdk.io/store/dbadapter"
+	storetypes "cosmossdk.io/store/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientStateTestSuite struct {
+	suite.Suite
+	cdc codec.Codec
+}
+
+func TestClientStateTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientStateTestSuite))
+}
+
+func (ts *ClientStateTestSuite) SetupTest() {
+	registry := codectypes.NewInterfaceRegistry()
+	clienttypes.RegisterInterfaces(registry)
+	Module{}.RegisterInterfaces(registry)
+	ts.cdc = codec.NewProtoCodec(registry)
+}
+
+func (ts *ClientStateTestSuite) clientState(height uint64) *ClientState {
+	latestHeight := clienttypes.NewHeight(0, height)
+	return &ClientState{
+		ChainId:            9999,
+		IbcStoreAddress:    common.HexToAddress("0xaa43d337145E8930d01cb4E60Abf6595C692921E").Bytes(),
+		IbcCommitmentsSlot: IBCCommitmentsSlot[:],
+		LatestHeight:       &latestHeight,
+		TrustingPeriod:     100 * time.Second,
+		MaxClockDrift:      1 * time.Second,
+		ForkSpecs:          GetForkParameters(Localnet),
+	}
+}
+
+func (ts *ClientStateTestSuite) newStore() storetypes.KVStore {
+	return dbadapter.Store{DB: dbm.NewMemDB()}
+}
+
+func (ts *ClientStateTestSuite) TestSuccessCheckSubstituteAndUpdateState() {
+	subject := ts.clientState(100)
+	subject.Frozen = true
+	subjectStore := ts.newStore()
+
+	substitute := ts.clientState(200)
+	substitute.TrustingPeriod = 200 * time.Second
+	substituteStore := ts.newStore()
+	substituteConsState := &ConsensusState{StateRoot: common.Hash{1}.Bytes(), Timestamp: 1}
+	setConsensusState(substituteStore, ts.cdc, substituteConsState, substitute.GetLatestHeight())
+
+	ts.Require().NoError(subject.CheckSubstituteAndUpdateState(sdk.Context{}, ts.cdc, subjectStore, substituteStore, substitute))
+
+	consState, found := getConsensusState(subjectStore, ts.cdc, substitute.GetLatestHeight())
+	ts.Require().True(found)
+	ts.Require().Equal(substituteConsState, consState)
+	updated := clienttypes.MustUnmarshalClientState(ts.cdc, subjectStore.Get([]byte("clientState"))).(*ClientState)
+	ts.Require().False(updated.Frozen)
+	ts.Require().Equal(substitute.GetLatestHeight(), updated.GetLatestHeight())
+	ts.Require().Equal(substitute.TrustingPeriod, updated.TrustingPeriod)
+	// subject itself is not modified
+	ts.Require().True(subject.Frozen)
+}
+
+func (ts *ClientStateTestSuite) TestErrorCheckSubstituteAndUpdateState() {
+	subject := ts.clientState(100)
+
+	// different chain
+	substitute := ts.clientState(200)
+	substitute.ChainId = 56
+	substituteStore := ts.newStore()
+	setConsensusState(substituteStore, ts.cdc, &ConsensusState{}, substitute.GetLatestHeight())
+	ts.Require().ErrorIs(subject.CheckSubstituteAndUpdateState(sdk.Context{}, ts.cdc, ts.newStore(), substituteStore, substitute), clienttypes.ErrInvalidSubstitute)
+
+	// different ibc contract
+	substitute = ts.clientState(200)
+	substitute.IbcStoreAddress = common.Address{}.Bytes()
+	ts.Require().ErrorIs(subject.CheckSubstituteAndUpdateState(sdk.Context{}, ts.cdc, ts.newStore(), substituteStore, substitute), clienttypes.ErrInvalidSubstitute)
+
+	// no consensus state
+	substitute = ts.clientState(300)
+	ts.Require().ErrorIs(subject.CheckSubstituteAndUpdateState(sdk.Context{}, ts.cdc, ts.newStore(), substituteStore, substitute), clienttypes.ErrConsensusStateNotFound)
+}
+
+func (ts *ClientStateTestSuite) TestSuccessVerifyUpgradeAndUpdateState() {
+	cs := ts.clientState(100)
+	upgraded := ts.clientState(200)
+	upgraded.TrustingPeriod = 0
+	upgraded.MaxClockDrift = 0
+	upgradedConsState := &ConsensusState{StateRoot: common.Hash{2}.Bytes(), Timestamp: 2}
+
+	store := ts.newStore()
+	stateRoot, clientProof, consStateProof := ts.makeUpgradeProofs(cs, upgraded, upgradedConsState)
+	setConsensusState(store, ts.cdc, &ConsensusState{StateRoot: stateRoot.Bytes()}, cs.GetLatestHeight())
+
+	ts.Require().NoError(cs.VerifyUpgradeAndUpdateState(sdk.Context{}, ts.cdc, store, upgraded, upgradedConsState, clientProof, consStateProof))
+
+	updated := clienttypes.MustUnmarshalClientState(ts.cdc, store.Get([]byte("clientState"))).(*ClientState)
+	ts.Require().Equal(upgraded.GetLatestHeight(), updated.GetLatestHeight())
+	ts.Require().Equal(cs.TrustingPeriod, updated.TrustingPeriod)
+	ts.Require().Equal(cs.MaxClockDrift, updated.MaxClockDrift)
+	consState, found := getConsensusState(store, ts.cdc, upgraded.GetLatestHeight())
+	ts.Require().True(found)
+	ts.Require().Equal(upgradedConsState, consState)
+}
+
+func (ts *ClientStateTestSuite) TestErrorVerifyUpgradeAndUpdateState() {
+	cs := ts.clientState(100)
+	upgraded := ts.clientState(200)
+	upgradedConsState := &ConsensusState{StateRoot: common.Hash{2}.Bytes(), Timestamp: 2}
+	stateRoot, clientProof, consStateProof := ts.makeUpgradeProofs(cs, upgraded, upgradedConsState)
+
+	// not greater height
+	ts.Require().ErrorIs(cs.VerifyUpgradeAndUpdateState(sdk.Context{}, ts.cdc, ts.newStore(), ts.clientState(100), upgradedConsState, clientProof, consStateProof), clienttypes.ErrInvalidUpgradeClient)
+
+	// no consensus state
+	ts.Require().ErrorIs(cs.VerifyUpgradeAndUpdateState(sdk.Context{}, ts.cdc, ts.newStore(), upgraded, upgradedConsState, clientProof, consStateProof), clienttypes.ErrConsensusStateNotFound)
+
+	// swapped proofs
+	store := ts.newStore()
+	setConsensusState(store, ts.cdc, &ConsensusState{StateRoot: stateRoot.Bytes()}, cs.GetLatestHeight())
+	ts.Require().ErrorIs(cs.VerifyUpgradeAndUpdateState(sdk.Context{}, ts.cdc, store, upgraded, upgradedConsState, consStateProof, clientProof), clienttypes.ErrInvalidUpgradeClient)
+
+	// different consensus state
+	ts.Require().ErrorIs(cs.VerifyUpgradeAndUpdateState(sdk.Context{}, ts.cdc, store, upgraded, &ConsensusState{}, clientProof, consStateProof), clienttypes.ErrInvalidUpgradeClient)
+}
+
+// makeUpgradeProofs returns the state root and the proofs of the IBC contract which commits the upgraded states.
+func (ts *ClientStateTestSuite) makeUpgradeProofs(cs *ClientState, upgraded *ClientState, upgradedConsState *ConsensusState) (common.Hash, []byte, []byte) {
+	bzClient, err := ts.cdc.MarshalInterface(upgraded.ZeroCustomFields())
+	ts.Require().NoError(err)
+	bzConsState, err := ts.cdc.MarshalInterface(upgradedConsState)
+	ts.Require().NoError(err)
+
+	height := cs.GetLatestHeight().GetRevisionHeight()
+	slot := common.BytesToHash(cs.IbcCommitmentsSlot)
+	storageKey := func(path string) []byte {
+		return crypto.Keccak256(crypto.Keccak256(append(crypto.Keccak256([]byte(path)), slot.Bytes()...)))
+	}
+	commitment := func(value []byte) []byte {
+		bz, err := rlp.EncodeToBytes(crypto.Keccak256(value))
+		ts.Require().NoError(err)
+		return bz
+	}
+	storageTrie := trie.NewEmpty(triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil))
+	ts.Require().NoError(storageTrie.Update(storageKey(UpgradedClientPath(height)), commitment(bzClient)))
+	ts.Require().NoError(storageTrie.Update(storageKey(UpgradedConsStatePath(height)), commitment(bzConsState)))
+
+	account, err := rlp.EncodeToBytes(&types.StateAccount{
+		Balance:  uint256.NewInt(0),
+		Root:     storageTrie.Hash(),
+		CodeHash: crypto.Keccak256(nil),
+	})
+	ts.Require().NoError(err)
+	accountTrie := trie.NewEmpty(triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil))
+	ts.Require().NoError(accountTrie.Update(crypto.Keccak256(cs.IbcStoreAddress), account))
+
+	accountProof := ts.prove(accountTrie, crypto.Keccak256(cs.IbcStoreAddress))
+	clientProof, err := (&ProveState{AccountProof: accountProof, CommitmentProof: ts.prove(storageTrie, storageKey(UpgradedClientPath(height)))}).Marshal()
+	ts.Require().NoError(err)
+	consStateProof, err := (&ProveState{AccountProof: accountProof, CommitmentProof: ts.prove(storageTrie, storageKey(UpgradedConsStatePath(height)))}).Marshal()
+	ts.Require().NoError(err)
+	return accountTrie.Hash(), clientProof, consStateProof
+}
+
+// prove returns the proof in the same format as eth_getProof of ethereum-ibc-relay-chain
+func (ts *ClientStateTestSuite) prove(t *trie.Trie, key []byte) []byte {
+	var nodes proofNodes
+	ts.Require().NoError(t.Prove(key, &nodes))
+	var decoded [][][]byte
+	for _, node := range nodes {
+		var v [][]byte
+		ts.Require().NoError(rlp.DecodeBytes(node, &v))
+		decoded = append(decoded, v)
+	}
+	bz, err := rlp.EncodeToBytes(decoded)
+	ts.Require().NoError(err)
+	return bz
+}
+
+type proofNodes [][]byte
+
+func (p *proofNodes) Put(_ []byte, value []byte) error {
+	*p = append(*p, value)
+	return nil
+}
+
+func (p *proofNodes) Delete(_ []byte) error {
+	panic("not implemented")
+}
+
+func (ts *ClientStateTestSuite) TestValidate() {
+	for _, network := range []Network{Localnet, Testnet, Mainnet} {
+		cs := ts.clientState(100)
+		cs.ForkSpecs = GetForkParameters(network)
+		ts.Require().NoError(cs.Validate(), network)
+	}
+
+	cases := map[string]func(cs *ClientState){
+		"zero chain id":          func(cs *ClientState) { cs.ChainId = 0 },
+		"invalid store address":  func(cs *ClientState) { cs.IbcStoreAddress = cs.IbcStoreAddress[1:] },
+		"invalid slot":           func(cs *ClientState) { cs.IbcCommitmentsSlot = cs.IbcCommitmentsSlot[1:] },
+		"nil latest height":      func(cs *ClientState) { cs.LatestHeight = nil },
+		"zero latest height":     func(cs *ClientState) { cs.LatestHeight = &clienttypes.Height{} },
+		"trusting period":        func(cs *ClientState) { cs.MaxClockDrift = cs.TrustingPeriod },
+		"empty fork specs":       func(cs *ClientState) { cs.ForkSpecs = nil },
+		"no height or timestamp": func(cs *ClientState) { cs.ForkSpecs[0].HeightOrTimestamp = nil },
+		"zero epoch length":      func(cs *ClientState) { cs.ForkSpecs[0].EpochLength = 0 },
+		"zero max turn length":   func(cs *ClientState) { cs.ForkSpecs[0].MaxTurnLength = 0 },
+		"unsorted height": func(cs *ClientState) {
+			cs.ForkSpecs[0].HeightOrTimestamp = &ForkSpec_Height{Height: 10}
+			cs.ForkSpecs[1].HeightOrTimestamp = &ForkSpec_Height{Height: 10}
+		},
+		"unsorted timestamp": func(cs *ClientState) {
+			cs.ForkSpecs[0].HeightOrTimestamp = &ForkSpec_Timestamp{Timestamp: 10}
+			cs.ForkSpecs[1].HeightOrTimestamp = &ForkSpec_Timestamp{Timestamp: 9}
+		},
+	}
+	for name, malleate := range cases {
+		cs := ts.clientState(100)
+		malleate(cs)
+		ts.Require().Error(cs.Validate(), name)
+	}
+}
+
+func (ts *ClientStateTestSuite) TestConsensusStateValidateBasic() {
+	cs := ConsensusState{
+		StateRoot:              common.Hash{}.Bytes(),
+		CurrentValidatorsHash:  common.Hash{}.Bytes(),
+		PreviousValidatorsHash: common.Hash{}.Bytes(),
+	}
+	ts.Require().NoError(cs.ValidateBasic())
+
+	invalid := cs
+	invalid.StateRoot = nil
+	ts.Require().Error(invalid.ValidateBasic())
+	invalid = cs
+	invalid.CurrentValidatorsHash = make([]byte, 31)
+	ts.Require().Error(invalid.ValidateBasic())
+	invalid = cs
+	invalid.PreviousValidatorsHash = make([]byte, 33)
+	ts.Require().Error(invalid.ValidateBasic())
+}
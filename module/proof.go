@@ -98,22 +98,19 @@ func verifyProof(rootHash common.Hash, key []byte, proof [][]byte) ([]byte, erro
 }
 
 func verifyMembership(root common.Hash, bzValueProof []byte, path string, commitment []byte) error {
-	var rawValueProof [][][]byte
-	if err := rlp.DecodeBytes(bzValueProof, &rawValueProof); err != nil {
-		return fmt.Errorf("rlp.DecodeBytes(bzValueProof, ...) failed: %v", err)
-	}
-	var valueProof [][]byte
-	for _, raw := range rawValueProof {
-		if bz, err := rlp.EncodeToBytes(raw); err != nil {
-			return fmt.Errorf("rlp.EncodeToBytes(raw) failed: %v", err)
-		} else {
-			valueProof = append(valueProof, bz)
-		}
+	return verifyCommitment(root, bzValueProof, common.Hash{}, path, commitment)
+}
+
+// verifyCommitment verifies the RLP encoded storage proof of the commitment in the IBC contract storage.
+func verifyCommitment(storageRoot common.Hash, commitmentProof []byte, slot common.Hash, path string, commitment []byte) error {
+	valueProof, err := decodeAccountProof(commitmentProof)
+	if err != nil {
+		return fmt.Errorf("rlp.DecodeBytes(commitmentProof, ...) failed: %v", err)
 	}
 
-	key := crypto.Keccak256(crypto.Keccak256(append(crypto.Keccak256([]byte(path)), common.Hash{}.Bytes()...)))
+	key := crypto.Keccak256(crypto.Keccak256(append(crypto.Keccak256([]byte(path)), slot.Bytes()...)))
 
-	recoveredCommitment, err := verifyProof(root, key, valueProof)
+	recoveredCommitment, err := verifyProof(storageRoot, key, valueProof)
 	if err != nil {
 		return fmt.Errorf("verifyProof failed: %v", err)
 	}
@@ -156,12 +153,25 @@ func decodeAccountProof(encodedAccountProof []byte) ([][]byte, error) {
 }
 
 func verifyAccount(target *types.Header, accountProof []byte, path common.Address) (*types.StateAccount, error) {
+	return verifyAccountByStateRoot(target.Root, accountProof, path)
+}
+
+// verifyStorageRoot returns the storage root of the account proven against the state root.
+func verifyStorageRoot(stateRoot common.Hash, accountProof []byte, path common.Address) (common.Hash, error) {
+	account, err := verifyAccountByStateRoot(stateRoot, accountProof, path)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return account.Root, nil
+}
+
+func verifyAccountByStateRoot(stateRoot common.Hash, accountProof []byte, path common.Address) (*types.StateAccount, error) {
 	decodedAccountProof, err := decodeAccountProof(accountProof)
 	if err != nil {
 		return nil, err
 	}
 	rlpAccount, err := verifyProof(
-		target.Root,
+		stateRoot,
 		crypto.Keccak256Hash(path.Bytes()).Bytes(),
 		decodedAccountProof,
 	)
@@ -176,10 +186,45 @@ func verifyAccount(target *types.Header, accountProof []byte, path common.Addres
 }
 
 func withValidators(ctx context.Context, headerFn getHeaderFn, height uint64, ethHeaders []*ETHHeader, forkSpecs []*ForkSpec) (core.Header, error) {
-	header := &Header{
-		Headers: ethHeaders,
+	epoch, err := queryEpochValidators(ctx, headerFn, height, forkSpecs)
+	if err != nil {
+		return nil, err
 	}
+	return &Header{
+		Headers:            ethHeaders,
+		CurrentValidators:  epoch.CurrentValidators,
+		CurrentTurnLength:  uint32(epoch.CurrentTurnLength),
+		PreviousValidators: epoch.PreviousValidators,
+		PreviousTurnLength: uint32(epoch.PreviousTurnLength),
+	}, nil
+}
 
+// epochValidators is the validator sets of the current and previous epochs at a height.
+// NextEpoch is the next epoch block number under the fork spec of the current epoch.
+type epochValidators struct {
+	CurrentEpoch       uint64
+	NextEpoch          uint64
+	CurrentValidators  Validators
+	CurrentTurnLength  uint8
+	PreviousValidators Validators
+	PreviousTurnLength uint8
+}
+
+// contains returns true if the block at `height` is in the epoch.
+func (e *epochValidators) contains(height uint64) bool {
+	return e.CurrentEpoch <= height && height < e.NextEpoch
+}
+
+// Signers returns the validator set that produces and votes for the block at `height`.
+// The current validator set takes effect at the checkpoint after the epoch block.
+func (e *epochValidators) Signers(height uint64) Validators {
+	if height-e.CurrentEpoch < e.PreviousValidators.Checkpoint(e.PreviousTurnLength) {
+		return e.PreviousValidators
+	}
+	return e.CurrentValidators
+}
+
+func queryEpochValidators(ctx context.Context, headerFn getHeaderFn, height uint64, forkSpecs []*ForkSpec) (*epochValidators, error) {
 	blockHeader, err := headerFn(ctx, height)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get block header : number = %d : %+v", height, err)
@@ -203,21 +248,17 @@ func withValidators(ctx context.Context, headerFn getHeaderFn, height uint64, et
 	log.GetLogger().DebugContext(ctx, "boundary epoch", "prevLast", boundaryEpochs.PrevLast, "currentFirst", boundaryEpochs.CurrentFirst, "intermediates", boundaryEpochs.Intermediates)
 
 	// Get validator set for verify headers
-	currentEpoch := boundaryEpochs.CurrentEpochBlockNumber(height)
-	var currentTurnLength uint8
-	header.CurrentValidators, currentTurnLength, err = queryValidatorSetAndTurnLength(ctx, headerFn, currentEpoch)
-	header.CurrentTurnLength = uint32(currentTurnLength)
+	epoch := &epochValidators{CurrentEpoch: boundaryEpochs.CurrentEpochBlockNumber(height)}
+	epoch.NextEpoch = boundaryEpochs.NextEpochBlockNumber(epoch.CurrentEpoch)
+	epoch.CurrentValidators, epoch.CurrentTurnLength, err = queryValidatorSetAndTurnLength(ctx, headerFn, epoch.CurrentEpoch)
 	if err != nil {
-		return nil, fmt.Errorf("ValidatorSet was not found in current epoch : number= %d : %+v", currentEpoch, err)
+		return nil, fmt.Errorf("ValidatorSet was not found in current epoch : number= %d : %+v", epoch.CurrentEpoch, err)
 	}
 
-	previousEpoch := boundaryEpochs.PreviousEpochBlockNumber(currentEpoch)
-	var previousTurnLength uint8
-	header.PreviousValidators, previousTurnLength, err = queryValidatorSetAndTurnLength(ctx, headerFn, previousEpoch)
-	header.PreviousTurnLength = uint32(previousTurnLength)
+	previousEpoch := boundaryEpochs.PreviousEpochBlockNumber(epoch.CurrentEpoch)
+	epoch.PreviousValidators, epoch.PreviousTurnLength, err = queryValidatorSetAndTurnLength(ctx, headerFn, previousEpoch)
 	if err != nil {
 		return nil, fmt.Errorf("ValidatorSet was not found in previous epoch : number = %d : %+v", previousEpoch, err)
 	}
-
-	return header, nil
+	return epoch, nil
 }
@@ -24,6 +24,10 @@ var IBCCommitmentsSlot = common.HexToHash("1ee222554989dda120e26ecacf756fe1235cd
 type Prover struct {
 	chain  Chain
 	config *ProverConfig
+	// homePath is the directory to persist the checkpoints of the updates
+	homePath string
+	// epochRefresh caches the epoch refresh height of the trusted height for CheckRefreshRequired
+	epochRefresh *epochRefresh
 }
 
 func NewProver(chain Chain, config *ProverConfig) core.Prover {
@@ -35,6 +39,7 @@ func NewProver(chain Chain, config *ProverConfig) core.Prover {
 
 // Init initializes the chain
 func (pr *Prover) Init(homePath string, timeout time.Duration, codec codec.ProtoCodecMarshaler, debug bool) error {
+	pr.homePath = homePath
 	return nil
 }
 
@@ -58,7 +63,7 @@ func (pr *Prover) CreateInitialLightClientState(ctx context.Context, height expo
 	}
 	var finalizedHeader []*ETHHeader
 	if height == nil {
-		_, finalizedHeader, err = queryLatestFinalizedHeader(ctx, pr.chain.Header, latestHeight.GetRevisionHeight(), pr.getForkParameters())
+		_, finalizedHeader, err = pr.queryLatestFinalizedHeader(ctx, latestHeight.GetRevisionHeight())
 	} else {
 		finalizedHeader, err = queryFinalizedHeader(ctx, pr.chain.Header, height.GetRevisionHeight(), latestHeight.GetRevisionHeight(), pr.getForkParameters())
 	}
@@ -69,9 +74,19 @@ func (pr *Prover) CreateInitialLightClientState(ctx context.Context, height expo
 		return nil, nil, fmt.Errorf("no finalized headers were found up to %d", latestHeight.GetRevisionHeight())
 	}
 	//Header should be Finalized, not necessarily Verifiable.
-	return pr.buildInitialState(ctx, &Header{
+	cs, consState, err := pr.buildInitialState(ctx, &Header{
 		Headers: finalizedHeader,
 	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = cs.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid client state: %w", err)
+	}
+	if err = consState.ValidateBasic(); err != nil {
+		return nil, nil, fmt.Errorf("invalid consensus state: %w", err)
+	}
+	return cs, consState, nil
 }
 
 // GetLatestFinalizedHeader returns the latest finalized header from the chain
@@ -90,7 +105,7 @@ func (pr *Prover) GetLatestFinalizedHeader(ctx context.Context) (out core.Header
 
 // GetLatestFinalizedHeaderByLatestHeight returns the latest finalized verifiable header from the chain
 func (pr *Prover) GetLatestFinalizedHeaderByLatestHeight(ctx context.Context, latestBlockNumber uint64) (core.Header, error) {
-	height, finalizedHeader, err := queryLatestFinalizedHeader(ctx, pr.chain.Header, latestBlockNumber, pr.getForkParameters())
+	height, finalizedHeader, err := pr.queryLatestFinalizedHeader(ctx, latestBlockNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -114,14 +129,175 @@ func (pr *Prover) SetupHeadersForUpdate(ctx context.Context, counterparty core.F
 	if err = pr.chain.Codec().UnpackAny(csRes.ClientState, &cs); err != nil {
 		return nil, err
 	}
-	if headers, err := pr.SetupHeadersForUpdateByLatestHeight(ctx, cs.GetLatestHeight(), header); err != nil {
+	return pr.streamHeadersForUpdate(ctx, counterparty, latestHeightOnDstChain, cs, header)
+}
+
+// SetupHeadersForUpdateToHeight creates the headers to update the client exactly to the target height
+// instead of the latest finalized height, for example to verify a commitment proof at the height.
+// The header at the target height is made verifiable with the nearest finalized descendants.
+// As SetupHeadersForUpdate, the headers beyond the update budget are submitted in the following updates.
+func (pr *Prover) SetupHeadersForUpdateToHeight(ctx context.Context, counterparty core.FinalityAwareChain, targetHeight uint64) (<-chan *core.HeaderOrError, error) {
+	latestHeightOnDstChain, err := counterparty.LatestHeight(ctx)
+	if err != nil {
 		return nil, err
-	} else {
-		return core.MakeHeaderStream(headers...), nil
 	}
+	csRes, err := counterparty.QueryClientState(core.NewQueryContext(ctx, latestHeightOnDstChain))
+	if err != nil {
+		return nil, fmt.Errorf("no client state found : SetupHeadersForUpdateToHeight: height = %d, %+v", latestHeightOnDstChain.GetRevisionHeight(), err)
+	}
+	var cs exported.ClientState
+	if err = pr.chain.Codec().UnpackAny(csRes.ClientState, &cs); err != nil {
+		return nil, err
+	}
+	trustedHeight := cs.GetLatestHeight().GetRevisionHeight()
+	if targetHeight == trustedHeight {
+		return core.MakeHeaderStream(), nil
+	}
+	if targetHeight < trustedHeight {
+		return nil, fmt.Errorf("target height %d is lower than the trusted height %d", targetHeight, trustedHeight)
+	}
+	latestHeight, err := pr.chain.LatestHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ethHeaders, err := queryFinalizedHeader(ctx, pr.chain.Header, targetHeight, latestHeight.GetRevisionHeight(), pr.getForkParameters())
+	if err != nil {
+		return nil, err
+	}
+	if ethHeaders == nil {
+		return nil, fmt.Errorf("%w: no finalized header found at or after the target height: target=%d, latest=%d",
+			ErrInsufficientVoteAttestation, targetHeight, latestHeight.GetRevisionHeight())
+	}
+	header, err := pr.withValidators(ctx, targetHeight, ethHeaders)
+	if err != nil {
+		return nil, err
+	}
+	return pr.streamHeadersForUpdate(ctx, counterparty, latestHeightOnDstChain, cs, header.(*Header))
+}
+
+// streamHeadersForUpdate returns the stream of the headers to update the client from the trusted state to the header.
+func (pr *Prover) streamHeadersForUpdate(ctx context.Context, counterparty core.FinalityAwareChain, latestHeightOnDstChain exported.Height, cs exported.ClientState, header *Header) (<-chan *core.HeaderOrError, error) {
+	consRes, err := counterparty.QueryClientConsensusState(core.NewQueryContext(ctx, latestHeightOnDstChain), cs.GetLatestHeight())
+	if err != nil {
+		return nil, fmt.Errorf("no consensus state found : height = %d, %+v", cs.GetLatestHeight().GetRevisionHeight(), err)
+	}
+	var cons exported.ConsensusState
+	if err = pr.chain.Codec().UnpackAny(consRes.ConsensusState, &cons); err != nil {
+		return nil, err
+	}
+	if err = pr.checkTrustedState(cs, cons, header, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// The headers are sent as soon as each one becomes verifiable, so the relayer can start submitting before the rest is built.
+	// The rest beyond the update budget is submitted in the following updates from the new trusted height.
+	stream := make(chan *core.HeaderOrError)
+	go func() {
+		defer close(stream)
+		batch := &batchBuilder{budget: pr.config.GetUpdateBudget()}
+		submit := func(h *Header) error {
+			ok, err := batch.add(h)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				log.GetLogger().InfoContext(ctx, "headers exceed the update budget", "submitting", batch.headers, "next", h.GetHeight().GetRevisionHeight())
+				return errStopStream
+			}
+			select {
+			case stream <- &core.HeaderOrError{Header: h}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		err := func() error {
+			trustedHeight := pr.newHeight(cs.GetLatestHeight().GetRevisionHeight())
+			// The headers built before the relayer restarted are submitted without querying them again
+			checkpoint, restored := pr.resumeCheckpoint(ctx, counterparty.Path().ClientID, trustedHeight, header)
+			for _, h := range restored {
+				if err := submit(h); err != nil {
+					return err
+				}
+				trustedHeight = pr.newHeight(h.GetHeight().GetRevisionHeight())
+			}
+			// Only the submitted headers are recorded, so a restart never replays a header the relayer has not received
+			return pr.streamHeadersForUpdateByLatestHeight(ctx, trustedHeight, header, func(h *Header) error {
+				if err := submit(h); err != nil {
+					return err
+				}
+				if err := checkpoint.append(h); err != nil {
+					log.GetLogger().ErrorContext(ctx, "failed to save the checkpoint", err, "path", checkpoint.path)
+				}
+				return nil
+			})
+		}()
+		if err = ignoreStopStream(err); err != nil {
+			select {
+			case stream <- &core.HeaderOrError{Error: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return stream, nil
+}
+
+// checkTrustedState rejects the update before any header is built if the counterparty client can no longer accept it.
+// The trusting period and the max clock drift of the counterparty client state take precedence over the prover config.
+func (pr *Prover) checkTrustedState(cs exported.ClientState, cons exported.ConsensusState, header *Header, now time.Time) error {
+	trustingPeriod, maxClockDrift := pr.trustingParameters(cs)
+	target, err := header.Target()
+	if err != nil {
+		return err
+	}
+	return validateTrustedState(trustingPeriod, maxClockDrift, consensusStateTime(cons), time.UnixMilli(int64(MilliTimestamp(target))), now)
+}
+
+// trustingParameters returns the trusting period and the max clock drift of the counterparty client state, or the configured ones if they are not set.
+func (pr *Prover) trustingParameters(cs exported.ClientState) (time.Duration, time.Duration) {
+	trustingPeriod := pr.config.GetTrustingPeriod()
+	maxClockDrift := pr.config.GetMaxClockDrift()
+	if parliaCs, ok := cs.(*ClientState); ok {
+		if parliaCs.TrustingPeriod > 0 {
+			trustingPeriod = parliaCs.TrustingPeriod
+		}
+		if parliaCs.MaxClockDrift > 0 {
+			maxClockDrift = parliaCs.MaxClockDrift
+		}
+	}
+	return trustingPeriod, maxClockDrift
+}
+
+// validateTrustedState returns ErrTrustedStateExpired if the trusted consensus state is out of the trusting period
+// and ErrHeaderFromFuture if the target header is newer than now plus the max clock drift.
+// A zero trusting period disables the expiration check.
+func validateTrustedState(trustingPeriod, maxClockDrift time.Duration, trustedTime, targetTime, now time.Time) error {
+	if trustingPeriod > 0 {
+		expiration := trustedTime.Add(trustingPeriod)
+		if !expiration.After(now) {
+			return fmt.Errorf("%w: trusted=%s, expiration=%s, now=%s", ErrTrustedStateExpired, trustedTime, expiration, now)
+		}
+	}
+	if targetTime.After(now.Add(maxClockDrift)) {
+		return fmt.Errorf("%w: target=%s, now=%s, maxClockDrift=%s", ErrHeaderFromFuture, targetTime, now, maxClockDrift)
+	}
+	return nil
 }
 
 func (pr *Prover) SetupHeadersForUpdateByLatestHeight(ctx context.Context, clientStateLatestHeight exported.Height, latestFinalizedHeader *Header) ([]core.Header, error) {
+	headers := make([]core.Header, 0)
+	err := pr.streamHeadersForUpdateByLatestHeight(ctx, clientStateLatestHeight, latestFinalizedHeader, func(h *Header) error {
+		headers = append(headers, h)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return headers, nil
+}
+
+// streamHeadersForUpdateByLatestHeight passes each valid header to yield as soon as it becomes verifiable.
+func (pr *Prover) streamHeadersForUpdateByLatestHeight(ctx context.Context, clientStateLatestHeight exported.Height, latestFinalizedHeader *Header, yield func(*Header) error) error {
 	queryVerifiableNeighboringEpochHeader := func(ctx context.Context, height uint64, limitHeight uint64) (core.Header, error) {
 		ethHeaders, err := queryFinalizedHeader(ctx, pr.chain.Header, height, limitHeight, pr.getForkParameters())
 		if err != nil {
@@ -135,21 +311,36 @@ func (pr *Prover) SetupHeadersForUpdateByLatestHeight(ctx context.Context, clien
 	}
 	latestHeight, err := pr.chain.LatestHeight(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return setupHeadersForUpdate(
+	submissionInterval, err := pr.submissionInterval(ctx, clientStateLatestHeight.GetRevisionHeight())
+	if err != nil {
+		return err
+	}
+	return streamHeadersForUpdate(
 		ctx,
-		queryVerifiableNeighboringEpochHeader,
+		pr.withFastFinalityRecovery(queryVerifiableNeighboringEpochHeader, pr.chain.ChainID(), submissionInterval),
 		pr.chain.Header,
-		clientStateLatestHeight,
+		pr.newHeight(clientStateLatestHeight.GetRevisionHeight()),
 		latestFinalizedHeader,
 		latestHeight,
 		GetForkParameters(Network(pr.config.Network)),
+		submissionInterval,
+		pr.config.GetHeaderQueryConcurrency(),
+		func(h *Header) error {
+			if err := h.ValidateBasic(); err != nil {
+				if view, viewErr := MarshalViewJSON(h); viewErr == nil {
+					log.GetLogger().DebugContext(ctx, "invalid header", "header", string(view))
+				}
+				return fmt.Errorf("invalid header: height=%d, %w", h.GetHeight().GetRevisionHeight(), err)
+			}
+			return yield(h)
+		},
 	)
 }
 
 func (pr *Prover) ProveState(ctx core.QueryContext, path string, value []byte) ([]byte, clienttypes.Height, error) {
-	proofHeight := toHeight(ctx.Height())
+	proofHeight := pr.newHeight(ctx.Height().GetRevisionHeight())
 	accountProof, commitmentProof, err := pr.getStateCommitmentProof(ctx.Context(), []byte(path), proofHeight)
 	if err != nil {
 		return nil, proofHeight, err
@@ -167,32 +358,11 @@ func (pr *Prover) ProveState(ctx core.QueryContext, path string, value []byte) (
 }
 
 func (pr *Prover) CheckRefreshRequired(ctx context.Context, counterparty core.ChainInfoICS02Querier) (bool, error) {
-	cpQueryHeight, err := counterparty.LatestHeight(ctx)
-	if err != nil {
-		return false, fmt.Errorf("failed to get the latest height of the counterparty chain: %+v", err)
-	}
-	cpQueryCtx := core.NewQueryContext(ctx, cpQueryHeight)
-
-	resCs, err := counterparty.QueryClientState(cpQueryCtx)
-	if err != nil {
-		return false, fmt.Errorf("failed to query the client state on the counterparty chain: %+v", err)
-	}
-
-	var cs exported.ClientState
-	if err = pr.chain.Codec().UnpackAny(resCs.ClientState, &cs); err != nil {
-		return false, fmt.Errorf("failed to unpack Any into tendermint client state: %+v", err)
-	}
-
-	resCons, err := counterparty.QueryClientConsensusState(cpQueryCtx, cs.GetLatestHeight())
+	cpQueryHeight, cs, cons, err := pr.queryCounterpartyClient(ctx, counterparty)
 	if err != nil {
-		return false, fmt.Errorf("failed to query the consensus state on the counterparty chain: %+v", err)
+		return false, err
 	}
-
-	var cons exported.ConsensusState
-	if err = pr.chain.Codec().UnpackAny(resCons.ConsensusState, &cons); err != nil {
-		return false, fmt.Errorf("failed to unpack Any into tendermint consensus state: %+v", err)
-	}
-	lcLastTimestamp := time.Unix(0, int64(cons.GetTimestamp()))
+	lcLastTimestamp := consensusStateTime(cons)
 
 	selfQueryHeight, err := pr.chain.LatestHeight(ctx)
 	if err != nil {
@@ -206,10 +376,6 @@ func (pr *Prover) CheckRefreshRequired(ctx context.Context, counterparty core.Ch
 
 	elapsedTime := selfTimestamp.Sub(lcLastTimestamp)
 
-	durationMulByFraction := func(d time.Duration, f *Fraction) time.Duration {
-		nsec := d.Nanoseconds() * int64(f.Numerator) / int64(f.Denominator)
-		return time.Duration(nsec) * time.Nanosecond
-	}
 	threshold := durationMulByFraction(pr.config.GetTrustingPeriod(), pr.config.GetRefreshThresholdRate())
 	if elapsedTime > threshold {
 		log.GetLogger().DebugContext(ctx, "needs refresh", "elapsedTime", elapsedTime, "threshold", threshold)
@@ -218,25 +384,105 @@ func (pr *Prover) CheckRefreshRequired(ctx context.Context, counterparty core.Ch
 
 	// Check if the block difference exceeds the threshold
 	blockDiffThreshold := pr.config.RefreshBlockDifferenceThreshold
-	if blockDiffThreshold == 0 || selfQueryHeight.GetRevisionHeight() < cs.GetLatestHeight().GetRevisionHeight() {
-		return false, nil
+	if blockDiffThreshold > 0 && selfQueryHeight.GetRevisionHeight() >= cs.GetLatestHeight().GetRevisionHeight() {
+		blockDiff := selfQueryHeight.GetRevisionHeight() - cs.GetLatestHeight().GetRevisionHeight()
+		if blockDiff > blockDiffThreshold {
+			log.GetLogger().DebugContext(ctx, "needs refresh due to block diff",
+				"chain", cpQueryHeight.GetRevisionHeight(),
+				"cs", cs.GetLatestHeight().GetRevisionHeight(),
+				"threshold", blockDiffThreshold)
+			return true, nil
+		}
+	}
+
+	// The client can not be updated once the chain moves past the last epoch the trusted consensus state can verify
+	epochRefreshHeight, err := pr.cachedEpochRefreshHeight(ctx, cs.GetLatestHeight().GetRevisionHeight())
+	if err != nil {
+		return false, err
 	}
-	blockDiff := selfQueryHeight.GetRevisionHeight() - cs.GetLatestHeight().GetRevisionHeight()
-	if blockDiff > blockDiffThreshold {
-		log.GetLogger().DebugContext(ctx, "needs refresh due to block diff",
-			"chain", cpQueryHeight.GetRevisionHeight(),
+	if selfQueryHeight.GetRevisionHeight() >= epochRefreshHeight {
+		log.GetLogger().DebugContext(ctx, "needs refresh due to epoch",
+			"chain", selfQueryHeight.GetRevisionHeight(),
 			"cs", cs.GetLatestHeight().GetRevisionHeight(),
-			"threshold", blockDiffThreshold)
+			"epochRefreshHeight", epochRefreshHeight)
 		return true, nil
 	}
 	return false, nil
 
 }
 
+// queryCounterpartyClient returns the client state and its latest consensus state on the counterparty chain.
+func (pr *Prover) queryCounterpartyClient(ctx context.Context, counterparty core.ChainInfoICS02Querier) (exported.Height, exported.ClientState, exported.ConsensusState, error) {
+	cpQueryHeight, err := counterparty.LatestHeight(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get the latest height of the counterparty chain: %+v", err)
+	}
+	cpQueryCtx := core.NewQueryContext(ctx, cpQueryHeight)
+
+	resCs, err := counterparty.QueryClientState(cpQueryCtx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to query the client state on the counterparty chain: %+v", err)
+	}
+
+	var cs exported.ClientState
+	if err = pr.chain.Codec().UnpackAny(resCs.ClientState, &cs); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to unpack Any into tendermint client state: %+v", err)
+	}
+
+	resCons, err := counterparty.QueryClientConsensusState(cpQueryCtx, cs.GetLatestHeight())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to query the consensus state on the counterparty chain: %+v", err)
+	}
+
+	var cons exported.ConsensusState
+	if err = pr.chain.Codec().UnpackAny(resCons.ConsensusState, &cons); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to unpack Any into tendermint consensus state: %+v", err)
+	}
+	return cpQueryHeight, cs, cons, nil
+}
+
 func (pr *Prover) withValidators(ctx context.Context, height uint64, ethHeaders []*ETHHeader) (core.Header, error) {
 	return withValidators(ctx, pr.chain.Header, height, ethHeaders, pr.getForkParameters())
 }
 
+// newHeight returns the height of the self chain with the configured revision number
+func (pr *Prover) newHeight(revisionHeight uint64) clienttypes.Height {
+	return clienttypes.NewHeight(pr.config.GetRevisionNumber(), revisionHeight)
+}
+
+// submissionInterval returns the interval of the intermediate headers from the trusted height.
+func (pr *Prover) submissionInterval(ctx context.Context, trustedHeight uint64) (uint64, error) {
+	trusted, err := pr.chain.Header(ctx, trustedHeight)
+	if err != nil {
+		return 0, err
+	}
+	forkSpec, _, err := FindTargetForkSpec(pr.getForkParameters(), trustedHeight, MilliTimestamp(trusted))
+	if err != nil {
+		return 0, err
+	}
+	return resolveSubmissionInterval(pr.config.SubmissionInterval, forkSpec.EpochLength)
+}
+
+// resolveSubmissionInterval returns the configured interval if it divides the epoch length, or the epoch length if it is not configured.
+func resolveSubmissionInterval(configured uint64, epochLength uint64) (uint64, error) {
+	if configured == 0 {
+		return epochLength, nil
+	}
+	if epochLength%configured != 0 {
+		return 0, fmt.Errorf("submission interval %d does not divide the epoch length %d", configured, epochLength)
+	}
+	return configured, nil
+}
+
+// finalitySearchDepth returns the maximum number of blocks searched for the latest finalized header.
+func (pr *Prover) finalitySearchDepth() uint64 {
+	if depth := pr.config.GetMaxFinalitySearchDepth(); depth > 0 {
+		return depth
+	}
+	forkSpecs := pr.getForkParameters()
+	return forkSpecs[len(forkSpecs)-1].EpochLength
+}
+
 func (pr *Prover) getForkParameters() []*ForkSpec {
 	return GetForkParameters(Network(pr.config.Network))
 }
@@ -274,7 +520,7 @@ func (pr *Prover) buildInitialState(ctx context.Context, dstHeader core.Header)
 		return nil, nil, err
 	}
 
-	latestHeight := toHeight(dstHeader.GetHeight())
+	latestHeight := pr.newHeight(dstHeader.GetHeight().GetRevisionHeight())
 	clientState := ClientState{
 		TrustingPeriod:     pr.config.TrustingPeriod,
 		MaxClockDrift:      pr.config.MaxClockDrift,
@@ -17,6 +17,7 @@ import (
 	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/relay/ethereum"
 	"github.com/datachainlab/ibc-hd-signer/pkg/hd"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/hyperledger-labs/yui-relayer/core"
 	"github.com/stretchr/testify/suite"
@@ -28,6 +29,7 @@ type mockChain struct {
 	chainTimestamp          map[exported.Height]uint64
 	latestHeight            uint64
 	trustedHeight           uint64
+	trustingPeriod          time.Duration
 }
 
 func (c *mockChain) GetProof(_ context.Context, _ common.Address, _ [][]byte, _ *big.Int) (*client.StateProof, error) {
@@ -56,7 +58,8 @@ func (c *mockChain) GetProof(_ context.Context, _ common.Address, _ [][]byte, _
 func (c *mockChain) QueryClientState(ctx core.QueryContext) (*clienttypes.QueryClientStateResponse, error) {
 	cHeight := clienttypes.NewHeight(ctx.Height().GetRevisionNumber(), c.trustedHeight)
 	cs := ClientState{
-		LatestHeight: &cHeight,
+		LatestHeight:   &cHeight,
+		TrustingPeriod: c.trustingPeriod,
 	}
 	anyClientState, err := codectypes.NewAnyWithValue(&cs)
 	if err != nil {
@@ -81,6 +84,13 @@ func (c *mockChain) Timestamp(_ context.Context, height exported.Height) (time.T
 	return time.Unix(int64(c.chainTimestamp[height]), 0), nil
 }
 
+func (c *mockChain) Header(_ context.Context, height uint64) (*types.Header, error) {
+	return &types.Header{
+		Number: big.NewInt(int64(height)),
+		Time:   c.chainTimestamp[clienttypes.NewHeight(0, height)],
+	}, nil
+}
+
 func (c *mockChain) LatestHeight(_ context.Context) (exported.Height, error) {
 	return clienttypes.NewHeight(0, c.latestHeight), nil
 }
@@ -172,6 +182,18 @@ func (ts *ProverTestSuite) TestQueryClientStateWithProof() {
 	ts.Require().Equal(common.Bytes2Hex(decoded.CommitmentProof), "f853f8518080a0143145e818eeff83817419a6632ea193fd1acaa4f791eb17282f623f38117f568080808080808080a016cbf6e0ba10512eb618d99a1e34025adb7e6f31d335bda7fb20c8bb95fb5b978080808080")
 }
 
+func (ts *ProverTestSuite) TestProveStateWithRevisionNumber() {
+	ts.prover.config.RevisionNumber = 1
+	defer func() {
+		ts.prover.config.RevisionNumber = 0
+	}()
+
+	ctx := core.NewQueryContext(context.Background(), clienttypes.NewHeight(0, 21400))
+	_, proofHeight, err := ts.prover.ProveState(ctx, host.FullClientStatePath(ts.prover.chain.Path().ClientID), nil)
+	ts.Require().NoError(err)
+	ts.Require().Equal(proofHeight, clienttypes.NewHeight(1, 21400))
+}
+
 func (ts *ProverTestSuite) TestConnection() {
 	res := &conntypes.QueryConnectionResponse{
 		Connection: &conntypes.ConnectionEnd{
@@ -255,13 +277,13 @@ func (ts *ProverTestSuite) TestCheckRefreshRequired() {
 	ts.chain.chainTimestamp[chainHeight] = uint64(now.Unix())
 
 	// should refresh by trusting_period
-	ts.chain.consensusStateTimestamp[csHeight] = uint64(now.Add(-51 * time.Second).UnixNano())
+	ts.chain.consensusStateTimestamp[csHeight] = uint64(now.Add(-51 * time.Second).UnixMilli())
 	required, err := ts.prover.CheckRefreshRequired(ctx, dst)
 	ts.Require().NoError(err)
 	ts.Require().True(required)
 
 	// needless by trusting_period
-	ts.chain.consensusStateTimestamp[csHeight] = uint64(now.Add(-50 * time.Second).UnixNano())
+	ts.chain.consensusStateTimestamp[csHeight] = uint64(now.Add(-50 * time.Second).UnixMilli())
 	required, err = ts.prover.CheckRefreshRequired(ctx, dst)
 	ts.Require().NoError(err)
 	ts.Require().False(required)
@@ -286,6 +308,134 @@ func (ts *ProverTestSuite) TestCheckRefreshRequired() {
 	required, err = ts.prover.CheckRefreshRequired(ctx, dst)
 	ts.Require().NoError(err)
 	ts.Require().False(required)
+
+	// should refresh by epoch regardless of the thresholds
+	// epochs of the trusted height 3 are 0, 200 and 400 so that the trusted consensus state can not verify the validators from 400
+	ts.chain.latestHeight = 300
+	ts.prover.config.RefreshBlockDifferenceThreshold = 0
+	required, err = ts.prover.CheckRefreshRequired(ctx, dst)
+	ts.Require().NoError(err)
+	ts.Require().True(required)
+
+	// needless by epoch
+	ts.chain.latestHeight = 299
+	required, err = ts.prover.CheckRefreshRequired(ctx, dst)
+	ts.Require().NoError(err)
+	ts.Require().False(required)
+
+	// the epoch refresh height is cached until the trusted height changes
+	ts.Require().Equal(&epochRefresh{trustedHeight: 3, height: 300}, ts.prover.epochRefresh)
+}
+
+func (ts *ProverTestSuite) TestNextRefresh() {
+	defer func() {
+		ts.chain.latestHeight = 0
+		ts.chain.trustedHeight = 0
+		ts.chain.trustingPeriod = 0
+	}()
+
+	now := time.Unix(1700000000, 0)
+	ts.chain.latestHeight = 5
+	ts.chain.trustedHeight = 3
+	for i := uint64(3); i <= 5; i++ {
+		ts.chain.chainTimestamp[clienttypes.NewHeight(0, i)] = uint64(now.Unix()) - (5 - i)
+	}
+	ts.chain.consensusStateTimestamp[clienttypes.NewHeight(0, 3)] = uint64(now.Add(-10 * time.Second).UnixMilli())
+
+	// The trusting period of the client state takes precedence over the config
+	ts.chain.trustingPeriod = 40 * time.Second
+	schedule, err := ts.prover.NextRefresh(context.Background(), ts.chain)
+	ts.Require().NoError(err)
+	ts.Require().Equal(RefreshReasonTrustingPeriod, schedule.Reason)
+	ts.Require().Equal(now.Add(10*time.Second).UnixMilli(), schedule.Deadline.UnixMilli())
+}
+
+func (ts *ProverTestSuite) TestSetupHeadersForUpdateToHeight() {
+	type dstMock struct {
+		Chain
+		core.FinalityAware
+	}
+	dst := dstMock{
+		Chain:         ts.prover.chain,
+		FinalityAware: ts.prover,
+	}
+	defer func() {
+		ts.chain.latestHeight = 0
+		ts.chain.trustedHeight = 0
+	}()
+
+	ctx := context.Background()
+	ts.chain.latestHeight = 5
+	ts.chain.trustedHeight = 3
+
+	// Needless to update to the trusted height
+	stream, err := ts.prover.SetupHeadersForUpdateToHeight(ctx, dst, 3)
+	ts.Require().NoError(err)
+	_, ok := <-stream
+	ts.Require().False(ok)
+
+	// The client can not go back
+	_, err = ts.prover.SetupHeadersForUpdateToHeight(ctx, dst, 2)
+	ts.Require().Error(err)
+
+	// The target is not finalized yet
+	_, err = ts.prover.SetupHeadersForUpdateToHeight(ctx, dst, 10)
+	ts.Require().ErrorIs(err, ErrInsufficientVoteAttestation)
+}
+
+func (ts *ProverTestSuite) TestResolveSubmissionInterval() {
+	interval, err := resolveSubmissionInterval(0, 1000)
+	ts.Require().NoError(err)
+	ts.Require().Equal(uint64(1000), interval)
+	interval, err = resolveSubmissionInterval(250, 1000)
+	ts.Require().NoError(err)
+	ts.Require().Equal(uint64(250), interval)
+	_, err = resolveSubmissionInterval(300, 1000)
+	ts.Require().Error(err)
+	_, err = resolveSubmissionInterval(2000, 1000)
+	ts.Require().Error(err)
+
+	config := &ProverConfig{Network: string(Mainnet), SubmissionInterval: 500}
+	ts.Require().NoError(config.Validate())
+	config.SubmissionInterval = 300
+	ts.Require().Error(config.Validate())
+}
+
+func (ts *ProverTestSuite) TestValidateTrustedState() {
+	now := time.Now()
+	trustingPeriod := 100 * time.Second
+	maxClockDrift := 1 * time.Second
+
+	// within trusting period and clock drift
+	err := validateTrustedState(trustingPeriod, maxClockDrift, now.Add(-99*time.Second), now.Add(maxClockDrift), now)
+	ts.Require().NoError(err)
+
+	// trusted state expired
+	err = validateTrustedState(trustingPeriod, maxClockDrift, now.Add(-trustingPeriod), now, now)
+	ts.Require().ErrorIs(err, ErrTrustedStateExpired)
+
+	// expiration check is disabled
+	err = validateTrustedState(0, maxClockDrift, now.Add(-trustingPeriod), now, now)
+	ts.Require().NoError(err)
+
+	// header from future
+	err = validateTrustedState(trustingPeriod, maxClockDrift, now, now.Add(maxClockDrift+time.Millisecond), now)
+	ts.Require().ErrorIs(err, ErrHeaderFromFuture)
+}
+
+func (ts *ProverTestSuite) TestCheckTrustedState() {
+	target := &types.Header{Number: big.NewInt(10), Time: uint64(time.Now().Unix())}
+	rlpTarget, err := rlp.EncodeToBytes(target)
+	ts.Require().NoError(err)
+	header := &Header{Headers: []*ETHHeader{{Header: rlpTarget}}}
+	cs := &ClientState{TrustingPeriod: 100 * time.Second, MaxClockDrift: time.Second}
+	now := time.Unix(int64(target.Time), 0)
+
+	cons := &ConsensusState{Timestamp: uint64(now.Add(-99 * time.Second).UnixMilli())}
+	ts.Require().NoError(ts.prover.checkTrustedState(cs, cons, header, now))
+
+	cons.Timestamp = uint64(now.Add(-100 * time.Second).UnixMilli())
+	ts.Require().ErrorIs(ts.prover.checkTrustedState(cs, cons, header, now), ErrTrustedStateExpired)
 }
 
 func (ts *ProverTestSuite) TestProveHostConsensusState() {
@@ -0,0 +1,307 @@
+package module
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/gogoproto/proto"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/hyperledger-labs/yui-relayer/config"
+	"github.com/hyperledger-labs/yui-relayer/coreutil"
+	"github.com/spf13/cobra"
+)
+
+func latestHeaderCmd(ctx *config.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "latest-header [chain-id]",
+		Short: "Show the latest finalized header of the chain",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prover, err := proverFromConfig(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			header, err := prover.GetLatestFinalizedHeader(cmd.Context())
+			if err != nil {
+				return err
+			}
+			view, err := NewHeaderView(header.(*Header))
+			if err != nil {
+				return err
+			}
+			return printJSON(cmd, view)
+		},
+	}
+}
+
+func validatorsCmd(ctx *config.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validators [chain-id] [epoch]",
+		Short: "Show the validator set and the turn length at the epoch block",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prover, err := proverFromConfig(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			epoch, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return err
+			}
+			validators, turnLength, err := queryValidatorSetAndTurnLength(cmd.Context(), prover.chain.Header, epoch)
+			if err != nil {
+				return err
+			}
+			if err = validateValidatorBytes(validators); err != nil {
+				return err
+			}
+			return printJSON(cmd, struct {
+				Epoch      uint64          `json:"epoch"`
+				TurnLength uint8           `json:"turn_length"`
+				Checkpoint uint64          `json:"checkpoint"`
+				Validators []ValidatorView `json:"validators"`
+			}{
+				Epoch:      epoch,
+				TurnLength: turnLength,
+				Checkpoint: epoch + validators.Checkpoint(turnLength),
+				Validators: NewValidatorViews(validators),
+			})
+		},
+	}
+}
+
+func forksCmd(ctx *config.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "forks [chain-id]",
+		Short: "Show the fork specs of the configured network with the resolved boundary heights",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prover, err := proverFromConfig(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			latestHeight, err := prover.chain.LatestHeight(cmd.Context())
+			if err != nil {
+				return err
+			}
+			latest, err := prover.chain.Header(cmd.Context(), latestHeight.GetRevisionHeight())
+			if err != nil {
+				return err
+			}
+			type forkView struct {
+				Height         *uint64 `json:"height,omitempty"`
+				Timestamp      *uint64 `json:"timestamp,omitempty"`
+				BoundaryHeight *uint64 `json:"boundary_height,omitempty"`
+				EpochLength    uint64  `json:"epoch_length"`
+				MaxTurnLength  uint64  `json:"max_turn_length"`
+				Error          string  `json:"error,omitempty"`
+			}
+			var views []forkView
+			for _, spec := range prover.getForkParameters() {
+				view := forkView{EpochLength: spec.EpochLength, MaxTurnLength: spec.MaxTurnLength}
+				if x, ok := spec.GetHeightOrTimestamp().(*ForkSpec_Height); ok {
+					view.Height = &x.Height
+				} else {
+					ts := spec.GetTimestamp()
+					view.Timestamp = &ts
+				}
+				// Boundary heights of timestamp forks are resolved only after activation
+				if view.Height != nil || MilliTimestamp(latest) >= *view.Timestamp {
+					boundary, err := GetBoundaryHeight(cmd.Context(), prover.chain.Header, latest.Number.Uint64(), *spec)
+					if err != nil {
+						view.Error = err.Error()
+					} else {
+						view.BoundaryHeight = &boundary.Height
+					}
+				}
+				views = append(views, view)
+			}
+			return printJSON(cmd, views)
+		},
+	}
+}
+
+func decodeCmd(ctx *config.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "decode [header|client-state|consensus-state|misbehaviour|prove-state|update-client|any] [hex]",
+		Short: "Decode a hex encoded Parlia message, MsgUpdateClient or a packed Any into a human-readable JSON",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bz, err := hex.DecodeString(strings.TrimPrefix(args[1], "0x"))
+			if err != nil {
+				return err
+			}
+			var msg proto.Message
+			switch args[0] {
+			case "header":
+				msg = &Header{}
+			case "client-state":
+				msg = &ClientState{}
+			case "consensus-state":
+				msg = &ConsensusState{}
+			case "misbehaviour":
+				msg = &Misbehaviour{}
+			case "prove-state":
+				msg = &ProveState{}
+			case "update-client":
+				msg = &clienttypes.MsgUpdateClient{}
+			case "any":
+				msg = &codectypes.Any{}
+			default:
+				return fmt.Errorf("unknown type: %s", args[0])
+			}
+			if err = proto.Unmarshal(bz, msg); err != nil {
+				return err
+			}
+			var view interface{}
+			switch m := msg.(type) {
+			case *codectypes.Any:
+				view, err = anyView(ctx.Codec, m)
+			case *clienttypes.MsgUpdateClient:
+				var clientMessage interface{}
+				if clientMessage, err = anyView(ctx.Codec, m.ClientMessage); err == nil {
+					view = map[string]interface{}{
+						"client_id":      m.ClientId,
+						"signer":         m.Signer,
+						"client_message": clientMessage,
+					}
+				}
+			default:
+				view, err = NewView(m)
+			}
+			if err != nil {
+				return err
+			}
+			return printJSON(cmd, view)
+		},
+	}
+}
+
+// anyView returns the view of the packed message if it is a Parlia message, otherwise its protobuf JSON.
+func anyView(cdc codec.ProtoCodecMarshaler, any *codectypes.Any) (interface{}, error) {
+	if any == nil {
+		return nil, nil
+	}
+	msg, err := cdc.InterfaceRegistry().Resolve(any.TypeUrl)
+	if err != nil {
+		return nil, err
+	}
+	if err = proto.Unmarshal(any.Value, msg); err != nil {
+		return nil, err
+	}
+	if view, err := NewView(msg); err == nil {
+		return view, nil
+	}
+	bz, err := cdc.MarshalJSON(any)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(bz), nil
+}
+
+func dryRunCmd(ctx *config.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "dry-run [path-name] [chain-id]",
+		Short: "Simulate the update of the Parlia client of the chain on the counterparty without submitting anything",
+		Long:  "Plan the headers to update the Parlia client, verify them against the consensus state trusted by the counterparty client and show the message sizes. It fails if the update would be rejected.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			self, counterparty, err := chainsFromPath(ctx, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			prover, err := coreutil.UnwrapProver[*Prover](self.Prover)
+			if err != nil {
+				return err
+			}
+			latest, err := prover.GetLatestFinalizedHeader(cmd.Context())
+			if err != nil {
+				return err
+			}
+			simulation, err := prover.SimulateUpdate(cmd.Context(), counterparty, latest)
+			if err != nil {
+				return err
+			}
+			if err = printJSON(cmd, simulation); err != nil {
+				return err
+			}
+			if !simulation.Succeeded() {
+				return fmt.Errorf("the update will be rejected by %s", simulation.ClientID)
+			}
+			return nil
+		},
+	}
+}
+
+func healthCmd(ctx *config.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "health [path-name] [chain-id]",
+		Short: "Show the health of the Parlia client of the chain on the counterparty",
+		Long:  "Show the lag of the Parlia client behind the chain, the trusting period left and whether the client state and the latest consensus state match the chain and the configuration.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			self, counterparty, err := chainsFromPath(ctx, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			prover, err := coreutil.UnwrapProver[*Prover](self.Prover)
+			if err != nil {
+				return err
+			}
+			health, err := prover.CheckClientHealth(cmd.Context(), counterparty)
+			if err != nil {
+				return err
+			}
+			return printJSON(cmd, health)
+		},
+	}
+}
+
+func finalityCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "finality [chain-id] [height]",
+		Short: "Explain why the header at the height is finalizable or not",
+		Long:  "Show the children and the grandchildren examined to justify the header at the height and its descendants, their vote sources and targets, the linkage condition each one failed and whether the search was cut short by k_ancestor_generation_depth.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prover, err := proverFromConfig(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			height, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return err
+			}
+			limitHeight, _ := cmd.Flags().GetUint64(flagLimitHeight)
+			diagnosis, err := prover.DiagnoseFinality(cmd.Context(), height, limitHeight)
+			if err != nil {
+				return err
+			}
+			return printJSON(cmd, diagnosis)
+		},
+	}
+	cmd.Flags().Uint64(flagLimitHeight, 0, "highest block to search the descendants. the height plus the submission interval is used if 0")
+	return cmd
+}
+
+// proverFromConfig returns the Parlia prover of the chain in the relayer config.
+func proverFromConfig(ctx *config.Context, chainID string) (*Prover, error) {
+	chain, err := ctx.Config.GetChain(chainID)
+	if err != nil {
+		return nil, err
+	}
+	return coreutil.UnwrapProver[*Prover](chain.Prover)
+}
+
+func printJSON(cmd *cobra.Command, v interface{}) error {
+	bz, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return printString(cmd, string(bz), "")
+}
@@ -0,0 +1,84 @@
+package module
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hyperledger-labs/yui-relayer/config"
+	"github.com/stretchr/testify/suite"
+)
+
+type QueryCmdTestSuite struct {
+	suite.Suite
+	ctx *config.Context
+}
+
+func TestQueryCmdTestSuite(t *testing.T) {
+	suite.Run(t, new(QueryCmdTestSuite))
+}
+
+func (ts *QueryCmdTestSuite) SetupTest() {
+	registry := codectypes.NewInterfaceRegistry()
+	clienttypes.RegisterInterfaces(registry)
+	Module{}.RegisterInterfaces(registry)
+	ts.ctx = &config.Context{Codec: codec.NewProtoCodec(registry)}
+}
+
+func (ts *QueryCmdTestSuite) execute(args ...string) (map[string]interface{}, error) {
+	cmd := decodeCmd(ts.ctx)
+	out := new(bytes.Buffer)
+	cmd.SetOut(out)
+	cmd.SetArgs(args)
+	if err := cmd.Execute(); err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	ts.Require().NoError(json.Unmarshal(out.Bytes(), &result))
+	return result, nil
+}
+
+func (ts *QueryCmdTestSuite) TestDecode() {
+	latestHeight := clienttypes.NewHeight(0, 100)
+	cs := &ClientState{ChainId: 56, LatestHeight: &latestHeight}
+	bz, err := cs.Marshal()
+	ts.Require().NoError(err)
+	result, err := ts.execute("client-state", "0x"+common.Bytes2Hex(bz))
+	ts.Require().NoError(err)
+	ts.Require().Equal(float64(56), result["chain_id"])
+
+	anyClientState, err := codectypes.NewAnyWithValue(cs)
+	ts.Require().NoError(err)
+	bz, err = anyClientState.Marshal()
+	ts.Require().NoError(err)
+	result, err = ts.execute("any", common.Bytes2Hex(bz))
+	ts.Require().NoError(err)
+	ts.Require().Equal(float64(56), result["chain_id"])
+
+	msg, err := clienttypes.NewMsgUpdateClient("xx-parlia-0", &Header{}, "signer")
+	ts.Require().NoError(err)
+	bz, err = msg.Marshal()
+	ts.Require().NoError(err)
+	result, err = ts.execute("update-client", common.Bytes2Hex(bz))
+	ts.Require().NoError(err)
+	ts.Require().Equal("xx-parlia-0", result["client_id"])
+	ts.Require().Contains(result["client_message"], "headers")
+
+	// non Parlia message is rendered as the protobuf JSON
+	anyHeight, err := codectypes.NewAnyWithValue(&clienttypes.MsgUpdateClient{ClientId: "07-tendermint-0"})
+	ts.Require().NoError(err)
+	bz, err = anyHeight.Marshal()
+	ts.Require().NoError(err)
+	result, err = ts.execute("any", common.Bytes2Hex(bz))
+	ts.Require().NoError(err)
+	ts.Require().Equal("07-tendermint-0", result["client_id"])
+
+	_, err = ts.execute("unknown", common.Bytes2Hex(bz))
+	ts.Require().Error(err)
+	_, err = ts.execute("header", "zz")
+	ts.Require().Error(err)
+}
@@ -0,0 +1,38 @@
+package module
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// CreateSubstituteLightClientState returns a pair of ClientState and ConsensusState for a substitute of the subject client.
+// The states are created by CreateInitialLightClientState and must be accepted by CheckSubstituteAndUpdateState of the subject.
+// If `height` is nil, the latest finalized height is selected automatically.
+func CreateSubstituteLightClientState(ctx context.Context, lc core.LightClient, subject exported.ClientState, height exported.Height) (*ClientState, *ConsensusState, error) {
+	subjectClientState, ok := subject.(*ClientState)
+	if !ok {
+		return nil, nil, fmt.Errorf("subject client must be Parlia client: %T", subject)
+	}
+	cs, consState, err := lc.CreateInitialLightClientState(ctx, height)
+	if err != nil {
+		return nil, nil, err
+	}
+	substituteClientState, ok := cs.(*ClientState)
+	if !ok {
+		return nil, nil, fmt.Errorf("substitute client must be Parlia client: %T", cs)
+	}
+	substituteConsState, ok := consState.(*ConsensusState)
+	if !ok {
+		return nil, nil, fmt.Errorf("substitute consensus state must be Parlia consensus state: %T", consState)
+	}
+	if !IsMatchingClientState(subjectClientState, substituteClientState) {
+		return nil, nil, fmt.Errorf("substitute client does not match subject client: subject chainId=%d, substitute chainId=%d", subjectClientState.ChainId, substituteClientState.ChainId)
+	}
+	if !substituteClientState.GetLatestHeight().GT(subjectClientState.GetLatestHeight()) {
+		return nil, nil, fmt.Errorf("substitute client height %s must be greater than subject client height %s", substituteClientState.GetLatestHeight(), subjectClientState.GetLatestHeight())
+	}
+	return substituteClientState, substituteConsState, nil
+}
@@ -0,0 +1,190 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+type RefreshReason string
+
+const (
+	// RefreshReasonTrustingPeriod is the time based refresh by RefreshThresholdRate
+	RefreshReasonTrustingPeriod RefreshReason = "trusting_period"
+	// RefreshReasonBlockDifference is the block difference based refresh by RefreshBlockDifferenceThreshold
+	RefreshReasonBlockDifference RefreshReason = "block_difference"
+	// RefreshReasonEpoch is the refresh before the chain moves past the last epoch the trusted consensus state can verify
+	RefreshReasonEpoch RefreshReason = "epoch"
+	// RefreshReasonForkBoundary is the refresh at the next fork boundary
+	RefreshReasonForkBoundary RefreshReason = "fork_boundary"
+)
+
+// RefreshSchedule is when the next refresh of the Parlia client on the counterparty chain is due.
+type RefreshSchedule struct {
+	// Deadline is the time at which the refresh is due. The ones of height based reasons are estimated from the block interval.
+	Deadline time.Time `json:"deadline"`
+	// Height is the height of the self chain at which the refresh is due, or 0 for time based reasons
+	Height uint64        `json:"height,omitempty"`
+	Reason RefreshReason `json:"reason"`
+}
+
+// Due returns true if the deadline has passed.
+func (s *RefreshSchedule) Due(now time.Time) bool {
+	return !s.Deadline.After(now)
+}
+
+// NextRefresh returns the earliest refresh deadline of the Parlia client on the counterparty chain and its reason.
+// Unlike CheckRefreshRequired, the caller can sleep until the deadline instead of polling the counterparty chain.
+func (pr *Prover) NextRefresh(ctx context.Context, counterparty core.ChainInfoICS02Querier) (*RefreshSchedule, error) {
+	_, cs, cons, err := pr.queryCounterpartyClient(ctx, counterparty)
+	if err != nil {
+		return nil, err
+	}
+	selfHeight, err := pr.chain.LatestHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the latest height of the self chain: %+v", err)
+	}
+	latest, err := pr.chain.Header(ctx, selfHeight.GetRevisionHeight())
+	if err != nil {
+		return nil, err
+	}
+	trusted, err := pr.chain.Header(ctx, cs.GetLatestHeight().GetRevisionHeight())
+	if err != nil {
+		return nil, err
+	}
+	interval, err := pr.blockInterval(ctx, trusted, latest)
+	if err != nil {
+		return nil, err
+	}
+	estimate := func(height uint64, reason RefreshReason) *RefreshSchedule {
+		return &RefreshSchedule{Deadline: estimateBlockTime(latest, interval, height), Height: height, Reason: reason}
+	}
+
+	trustedTime := consensusStateTime(cons)
+	trustingPeriod, _ := pr.trustingParameters(cs)
+	schedules := []*RefreshSchedule{{
+		Deadline: trustedTime.Add(durationMulByFraction(trustingPeriod, pr.config.GetRefreshThresholdRate())),
+		Reason:   RefreshReasonTrustingPeriod,
+	}}
+	if blockDiffThreshold := pr.config.RefreshBlockDifferenceThreshold; blockDiffThreshold > 0 {
+		schedules = append(schedules, estimate(trusted.Number.Uint64()+blockDiffThreshold, RefreshReasonBlockDifference))
+	}
+	epochHeight, err := pr.epochRefreshHeight(ctx, trusted)
+	if err != nil {
+		return nil, err
+	}
+	schedules = append(schedules, estimate(epochHeight, RefreshReasonEpoch))
+	if forkSpec := nextForkSpec(pr.getForkParameters(), trusted); forkSpec != nil {
+		switch x := forkSpec.GetHeightOrTimestamp().(type) {
+		case *ForkSpec_Height:
+			schedules = append(schedules, estimate(x.Height, RefreshReasonForkBoundary))
+		case *ForkSpec_Timestamp:
+			schedules = append(schedules, &RefreshSchedule{Deadline: time.UnixMilli(int64(x.Timestamp)), Reason: RefreshReasonForkBoundary})
+		}
+	}
+	return earliestRefresh(schedules), nil
+}
+
+// epochRefreshHeight returns the height from which the client must be refreshed regardless of the thresholds.
+// The trusted consensus state can verify the headers only in its epoch and the next one,
+// so the refresh is due at the middle of the next epoch, leaving time for the finality of the submitted header.
+func (pr *Prover) epochRefreshHeight(ctx context.Context, trusted *types.Header) (uint64, error) {
+	currentForkSpec, prevForkSpec, err := FindTargetForkSpec(pr.getForkParameters(), trusted.Number.Uint64(), MilliTimestamp(trusted))
+	if err != nil {
+		return 0, err
+	}
+	boundaryHeight, err := GetBoundaryHeight(ctx, pr.chain.Header, trusted.Number.Uint64(), *currentForkSpec)
+	if err != nil {
+		return 0, err
+	}
+	boundaryEpochs, err := boundaryHeight.GetBoundaryEpochs(prevForkSpec)
+	if err != nil {
+		return 0, err
+	}
+	nextEpoch := boundaryEpochs.NextEpochBlockNumber(boundaryEpochs.CurrentEpochBlockNumber(trusted.Number.Uint64()))
+	unverifiableEpoch := boundaryEpochs.NextEpochBlockNumber(nextEpoch)
+	return nextEpoch + (unverifiableEpoch-nextEpoch)/2, nil
+}
+
+type epochRefresh struct {
+	trustedHeight uint64
+	height        uint64
+}
+
+// cachedEpochRefreshHeight returns the epoch refresh height of the trusted height.
+// It is cached until the trusted height changes, so that polling CheckRefreshRequired does not query the trusted header every time.
+func (pr *Prover) cachedEpochRefreshHeight(ctx context.Context, trustedHeight uint64) (uint64, error) {
+	if cached := pr.epochRefresh; cached != nil && cached.trustedHeight == trustedHeight {
+		return cached.height, nil
+	}
+	trusted, err := pr.chain.Header(ctx, trustedHeight)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the trusted header of the self chain: %+v", err)
+	}
+	height, err := pr.epochRefreshHeight(ctx, trusted)
+	if err != nil {
+		return 0, err
+	}
+	pr.epochRefresh = &epochRefresh{trustedHeight: trustedHeight, height: height}
+	return height, nil
+}
+
+// blockInterval returns the average block interval between the trusted header and the latest header.
+func (pr *Prover) blockInterval(ctx context.Context, trusted, latest *types.Header) (time.Duration, error) {
+	from := trusted
+	if from.Number.Uint64() >= latest.Number.Uint64() {
+		if latest.Number.Uint64() == 0 {
+			return 0, fmt.Errorf("no block to estimate the block interval")
+		}
+		var err error
+		if from, err = pr.chain.Header(ctx, latest.Number.Uint64()-1); err != nil {
+			return 0, err
+		}
+	}
+	elapsed := time.Duration(MilliTimestamp(latest)-MilliTimestamp(from)) * time.Millisecond
+	return elapsed / time.Duration(latest.Number.Uint64()-from.Number.Uint64()), nil
+}
+
+// nextForkSpec returns the first fork spec activated after the trusted header.
+func nextForkSpec(forkSpecs []*ForkSpec, trusted *types.Header) *ForkSpec {
+	for _, forkSpec := range forkSpecs {
+		switch x := forkSpec.GetHeightOrTimestamp().(type) {
+		case *ForkSpec_Height:
+			if x.Height > trusted.Number.Uint64() {
+				return forkSpec
+			}
+		case *ForkSpec_Timestamp:
+			if x.Timestamp > MilliTimestamp(trusted) {
+				return forkSpec
+			}
+		}
+	}
+	return nil
+}
+
+// estimateBlockTime returns the estimated time of the block at `height`, or the time of the latest header if it has been produced.
+func estimateBlockTime(latest *types.Header, interval time.Duration, height uint64) time.Time {
+	latestTime := time.UnixMilli(int64(MilliTimestamp(latest)))
+	if height <= latest.Number.Uint64() {
+		return latestTime
+	}
+	return latestTime.Add(time.Duration(height-latest.Number.Uint64()) * interval)
+}
+
+func earliestRefresh(schedules []*RefreshSchedule) *RefreshSchedule {
+	earliest := schedules[0]
+	for _, s := range schedules[1:] {
+		if s.Deadline.Before(earliest.Deadline) {
+			earliest = s
+		}
+	}
+	return earliest
+}
+
+func durationMulByFraction(d time.Duration, f *Fraction) time.Duration {
+	nsec := d.Nanoseconds() * int64(f.Numerator) / int64(f.Denominator)
+	return time.Duration(nsec) * time.Nanosecond
+}
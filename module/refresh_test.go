@@ -0,0 +1,58 @@
+package module
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type RefreshTestSuite struct {
+	suite.Suite
+}
+
+func TestRefreshTestSuite(t *testing.T) {
+	suite.Run(t, new(RefreshTestSuite))
+}
+
+func (ts *RefreshTestSuite) header(number int64, milliTimestamp uint64) *types.Header {
+	return &types.Header{Number: big.NewInt(number), Time: milliTimestamp / 1000}
+}
+
+func (ts *RefreshTestSuite) TestNextForkSpec() {
+	forkSpecs := []*ForkSpec{
+		{HeightOrTimestamp: &ForkSpec_Height{Height: 0}},
+		{HeightOrTimestamp: &ForkSpec_Height{Height: 100}},
+		{HeightOrTimestamp: &ForkSpec_Timestamp{Timestamp: 2000_000}},
+	}
+	ts.Require().Equal(forkSpecs[1], nextForkSpec(forkSpecs, ts.header(99, 1000_000)))
+	ts.Require().Equal(forkSpecs[2], nextForkSpec(forkSpecs, ts.header(100, 1000_000)))
+	ts.Require().Nil(nextForkSpec(forkSpecs, ts.header(100, 2000_000)))
+}
+
+func (ts *RefreshTestSuite) TestEstimateBlockTime() {
+	latest := ts.header(100, 1000_000)
+	latestTime := time.UnixMilli(1000_000)
+	ts.Require().Equal(latestTime, estimateBlockTime(latest, time.Second, 99))
+	ts.Require().Equal(latestTime, estimateBlockTime(latest, time.Second, 100))
+	ts.Require().Equal(latestTime.Add(10*time.Second), estimateBlockTime(latest, time.Second, 110))
+}
+
+func (ts *RefreshTestSuite) TestEarliestRefresh() {
+	now := time.Now()
+	schedules := []*RefreshSchedule{
+		{Deadline: now.Add(time.Hour), Reason: RefreshReasonTrustingPeriod},
+		{Deadline: now.Add(time.Minute), Height: 10, Reason: RefreshReasonEpoch},
+		{Deadline: now.Add(time.Minute), Height: 10, Reason: RefreshReasonForkBoundary},
+	}
+	earliest := earliestRefresh(schedules)
+	ts.Require().Equal(RefreshReasonEpoch, earliest.Reason)
+	ts.Require().False(earliest.Due(now))
+	ts.Require().True(earliest.Due(now.Add(time.Minute)))
+}
+
+func (ts *RefreshTestSuite) TestDurationMulByFraction() {
+	ts.Require().Equal(50*time.Second, durationMulByFraction(100*time.Second, &Fraction{Numerator: 1, Denominator: 2}))
+}
@@ -4,16 +4,13 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/errors"
 	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
 	"github.com/cosmos/ibc-go/v8/modules/core/exported"
 	"github.com/hyperledger-labs/yui-relayer/core"
 	"github.com/hyperledger-labs/yui-relayer/log"
 )
 
-// Maximum header interval to be submitted to LCP
-// Supports after Maxwell HF only
-const skip = 1000
-
 type queryVerifiableNeighboringEpochHeaderFn = func(context.Context, uint64, uint64) (core.Header, error)
 
 func shouldSubmitBoundaryTimestampHeader(
@@ -53,6 +50,9 @@ func shouldSubmitBoundaryTimestampHeader(
 	return nil, 0, nil
 }
 
+// errStopStream is returned by the yield function of streamHeadersForUpdate to stop streaming without an error.
+var errStopStream = errors.New("stop streaming")
+
 func setupHeadersForUpdate(
 	ctx context.Context,
 	queryVerifiableNeighboringEpochHeader queryVerifiableNeighboringEpochHeaderFn,
@@ -61,33 +61,60 @@ func setupHeadersForUpdate(
 	latestFinalizedHeader *Header,
 	latestHeight exported.Height,
 	forkSpecs []*ForkSpec,
+	submissionInterval uint64,
+	concurrency uint32,
 ) ([]core.Header, error) {
+	targetHeaders := make([]core.Header, 0)
+	err := streamHeadersForUpdate(ctx, queryVerifiableNeighboringEpochHeader, getHeader, clientStateLatestHeight, latestFinalizedHeader, latestHeight, forkSpecs, submissionInterval, concurrency, func(h *Header) error {
+		targetHeaders = append(targetHeaders, h)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return targetHeaders, nil
+}
+
+// streamHeadersForUpdate passes each header to yield as soon as it becomes verifiable, in the order of submission.
+// The trusted height of each header is the height of the previous one, so the headers can be submitted before the rest is built.
+// Streaming stops without an error when yield returns errStopStream.
+func streamHeadersForUpdate(
+	ctx context.Context,
+	queryVerifiableNeighboringEpochHeader queryVerifiableNeighboringEpochHeaderFn,
+	getHeader getHeaderFn,
+	clientStateLatestHeight exported.Height,
+	latestFinalizedHeader *Header,
+	latestHeight exported.Height,
+	forkSpecs []*ForkSpec,
+	submissionInterval uint64,
+	concurrency uint32,
+	yield func(*Header) error,
+) error {
 	logger := log.GetLogger()
 	logger.DebugContext(ctx, "setupHeadersForUpdate start", "target", latestFinalizedHeader.GetHeight().GetRevisionHeight())
-	targetHeaders := make([]core.Header, 0)
 
 	// Needless to update already saved state
 	if clientStateLatestHeight.GetRevisionHeight() == latestFinalizedHeader.GetHeight().GetRevisionHeight() {
-		return targetHeaders, nil
+		return nil
 	}
 	savedLatestHeight := clientStateLatestHeight.GetRevisionHeight()
 
 	trustedBlock, err := getHeader(ctx, savedLatestHeight)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	trustedCurrentForkSpec, trustedPreviousForkSpec, err := FindTargetForkSpec(forkSpecs, savedLatestHeight, MilliTimestamp(trustedBlock))
 	if err != nil {
-		return nil, err
+		return err
 	}
 	trustedBoundaryHeight, err := GetBoundaryHeight(ctx, getHeader, savedLatestHeight, *trustedCurrentForkSpec)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	trustedBoundaryEpochs, err := trustedBoundaryHeight.GetBoundaryEpochs(trustedPreviousForkSpec)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	trustedEpochHeight := trustedBoundaryEpochs.CurrentEpochBlockNumber(savedLatestHeight)
@@ -96,32 +123,119 @@ func setupHeadersForUpdate(
 	// If the condition is timestamp. we must submit the header with the timestamp
 	nextForkBoundaryTs, nextForkBoundaryHeightMinus1, err := shouldSubmitBoundaryTimestampHeader(ctx, getHeader, savedLatestHeight, latestFinalizedHeader.GetHeight().GetRevisionHeight(), forkSpecs)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	firstUnsaved := trustedEpochHeight + skip
+	firstUnsaved := trustedEpochHeight + submissionInterval
 	for firstUnsaved <= savedLatestHeight {
-		firstUnsaved += skip
+		firstUnsaved += submissionInterval
 	}
 
-	submittingHeights := makeSubmittingHeights(latestFinalizedHeight, savedLatestHeight, firstUnsaved, nextForkBoundaryTs, nextForkBoundaryHeightMinus1)
+	submittingHeights := makeSubmittingHeights(latestFinalizedHeight, savedLatestHeight, firstUnsaved, nextForkBoundaryTs, nextForkBoundaryHeightMinus1, submissionInterval)
 	logger.DebugContext(ctx, "submitting heights", "heights", submittingHeights, "trusted height", savedLatestHeight, "trusted epoch", trustedEpochHeight, "first unsaved", firstUnsaved)
 
-	trustedHeight := clientStateLatestHeight.GetRevisionHeight()
-	for _, submittingHeight := range submittingHeights {
-		verifiableHeader, err := setupIntermediateHeader(ctx, queryVerifiableNeighboringEpochHeader, submittingHeight, latestHeight)
-		if err != nil {
-			return nil, err
+	// The revision number of the client state is used for all heights because a header never crosses revisions
+	revisionNumber := clientStateLatestHeight.GetRevisionNumber()
+	trustedHeight := clienttypes.NewHeight(revisionNumber, clientStateLatestHeight.GetRevisionHeight())
+	emit := func(h *Header) error {
+		trusted := trustedHeight
+		h.TrustedHeight = &trusted
+		logger.DebugContext(ctx, "setupHeadersForUpdate end", "target", h.GetHeight(), "trusted", trustedHeight, "headerSize", len(h.Headers))
+		if err := yield(h); err != nil {
+			return err
 		}
+		trustedHeight = clienttypes.NewHeight(revisionNumber, h.GetHeight().GetRevisionHeight())
+		return nil
+	}
+	query := func(ctx context.Context, submittingHeight uint64) (core.Header, error) {
+		return setupIntermediateHeader(ctx, queryVerifiableNeighboringEpochHeader, submittingHeight, latestHeight, submissionInterval)
+	}
+	completed, err := queryConcurrently(ctx, submittingHeights, concurrency, query, func(submittingHeight uint64, verifiableHeader core.Header) (bool, error) {
 		if verifiableHeader == nil {
-			logger.ErrorContext(ctx, "[FastFinalityError]", fmt.Errorf("insufficient vote attestation: submittingHeight=%d, trusted=%d", submittingHeight, trustedHeight))
-			return withTrustedHeight(ctx, targetHeaders, clientStateLatestHeight), nil
+			logger.ErrorContext(ctx, "[FastFinalityError]", fmt.Errorf("%w: submittingHeight=%d, trusted=%d", ErrInsufficientVoteAttestation, submittingHeight, trustedHeight.GetRevisionHeight()))
+			return false, nil
+		}
+		if err := emit(verifiableHeader.(*Header)); err != nil {
+			return false, err
 		}
-		targetHeaders = append(targetHeaders, verifiableHeader)
-		trustedHeight = submittingHeight
 		logger.DebugContext(ctx, "setup epoch header", "trusted", trustedHeight, "height", submittingHeight)
+		return true, nil
+	})
+	if err != nil || !completed {
+		return ignoreStopStream(err)
+	}
+	return ignoreStopStream(emit(latestFinalizedHeader))
+}
+
+func ignoreStopStream(err error) error {
+	if errors.Is(err, errStopStream) {
+		return nil
 	}
-	return withTrustedHeight(ctx, append(targetHeaders, latestFinalizedHeader), clientStateLatestHeight), nil
+	return err
+}
+
+// queryConcurrently queries the headers at the heights with at most `concurrency` queries in flight,
+// and passes the results to yield in the order of the heights. A query holds its slot until its result is yielded,
+// so the results waiting for yield are bounded as well and `concurrency` of 1 queries one after another.
+// It returns false without querying the rest when yield returns false.
+func queryConcurrently(
+	ctx context.Context,
+	heights []uint64,
+	concurrency uint32,
+	query func(context.Context, uint64) (core.Header, error),
+	yield func(uint64, core.Header) (bool, error),
+) (bool, error) {
+	if concurrency == 0 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		header core.Header
+		err    error
+	}
+	results := make([]chan result, len(heights))
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+	slots := make(chan struct{}, concurrency)
+	go func() {
+		for i, height := range heights {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			go func(i int, height uint64) {
+				header, err := query(ctx, height)
+				results[i] <- result{header, err}
+			}(i, height)
+		}
+	}()
+
+	for i, height := range heights {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		var r result
+		select {
+		case r = <-results[i]:
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+		if r.err != nil {
+			return false, r.err
+		}
+		if ok, err := yield(height, r.header); !ok || err != nil {
+			return false, err
+		}
+		<-slots
+	}
+	return true, nil
 }
 
 // Get verifiable headers. This method must be executed at block intervals that do not miss any epochs.
@@ -130,27 +244,12 @@ func setupIntermediateHeader(
 	queryVerifiableHeader queryVerifiableNeighboringEpochHeaderFn,
 	submittingHeight uint64,
 	latestHeight exported.Height,
+	submissionInterval uint64,
 ) (core.Header, error) {
-	return queryVerifiableHeader(ctx, submittingHeight, minUint64(submittingHeight+skip, latestHeight.GetRevisionHeight()))
-}
-
-func withTrustedHeight(ctx context.Context, targetHeaders []core.Header, clientStateLatestHeight exported.Height) []core.Header {
-	logger := log.GetLogger()
-	for i, h := range targetHeaders {
-		var trustedHeight clienttypes.Height
-		if i == 0 {
-			trustedHeight = toHeight(clientStateLatestHeight)
-		} else {
-			trustedHeight = toHeight(targetHeaders[i-1].GetHeight())
-		}
-		h.(*Header).TrustedHeight = &trustedHeight
-
-		logger.DebugContext(ctx, "setupHeadersForUpdate end", "target", h.GetHeight(), "trusted", trustedHeight, "headerSize", len(h.(*Header).Headers))
-	}
-	return targetHeaders
+	return queryVerifiableHeader(ctx, submittingHeight, minUint64(submittingHeight+submissionInterval, latestHeight.GetRevisionHeight()))
 }
 
-func makeSubmittingHeights(latestFinalizedHeight uint64, savedLatestHeight uint64, firstUnsaved uint64, nextForkBoundaryTs *uint64, nextForkBoundaryHeightMinus1 uint64) []uint64 {
+func makeSubmittingHeights(latestFinalizedHeight uint64, savedLatestHeight uint64, firstUnsaved uint64, nextForkBoundaryTs *uint64, nextForkBoundaryHeightMinus1 uint64, submissionInterval uint64) []uint64 {
 	var submittingHeights []uint64
 	if latestFinalizedHeight < firstUnsaved {
 		if nextForkBoundaryTs != nil && savedLatestHeight < nextForkBoundaryHeightMinus1 && nextForkBoundaryHeightMinus1 < latestFinalizedHeight {
@@ -158,7 +257,7 @@ func makeSubmittingHeights(latestFinalizedHeight uint64, savedLatestHeight uint6
 		}
 	} else {
 		var temp []uint64
-		for epochCandidate := firstUnsaved; epochCandidate < latestFinalizedHeight; epochCandidate += skip {
+		for epochCandidate := firstUnsaved; epochCandidate < latestFinalizedHeight; epochCandidate += submissionInterval {
 			temp = append(temp, epochCandidate)
 		}
 		if nextForkBoundaryTs != nil {
@@ -2,6 +2,8 @@ package module
 
 import (
 	"context"
+	"fmt"
+	"sync"
 
 	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
 	types2 "github.com/ethereum/go-ethereum/core/types"
@@ -26,6 +28,9 @@ func (ts *SetupTestSuite) SetupTest() {
 	ts.Require().NoError(err)
 }
 
+// skip is the submission interval of the epoch length after Maxwell HF
+const skip = 1000
+
 var forkSpecsAfterMaxwell = []*ForkSpec{
 	{
 		// Must Set Milli timestamp
@@ -63,7 +68,7 @@ func (ts *SetupTestSuite) TestSuccess_setupHeadersForUpdate_neighboringEpoch() {
 			}, nil
 		}
 
-		targets, err := setupHeadersForUpdate(context.Background(), neighborFn, headerFn, clientStateLatestHeight, latestFinalizedHeader, clienttypes.NewHeight(0, 100000), forkSpecsAfterMaxwell)
+		targets, err := setupHeadersForUpdate(context.Background(), neighborFn, headerFn, clientStateLatestHeight, latestFinalizedHeader, clienttypes.NewHeight(0, 100000), forkSpecsAfterMaxwell, skip, 1)
 		ts.Require().NoError(err)
 		ts.Require().Len(targets, expected)
 		for i, h := range targets {
@@ -123,7 +128,7 @@ func (ts *SetupTestSuite) TestSuccess_setupHeadersForUpdate_allEmpty() {
 		}
 		targets, err := setupHeadersForUpdate(context.Background(), neighboringEpochFn, headerFn, clientStateLatestHeight, latestFinalizedHeader,
 			clienttypes.NewHeight(0,
-				1000000), forkSpecsAfterMaxwell)
+				1000000), forkSpecsAfterMaxwell, skip, 1)
 		ts.Require().NoError(err)
 		ts.Require().Len(targets, expected)
 	}
@@ -193,7 +198,7 @@ func (ts *SetupTestSuite) TestSuccess_setupHeadersForUpdate_withHFBoundary() {
 			}, nil
 		}
 
-		targets, err := setupHeadersForUpdate(context.Background(), neighborFn, headerFn, clientStateLatestHeight, latestFinalizedHeader, clienttypes.NewHeight(0, 100000), forkSpecs)
+		targets, err := setupHeadersForUpdate(context.Background(), neighborFn, headerFn, clientStateLatestHeight, latestFinalizedHeader, clienttypes.NewHeight(0, 100000), forkSpecs, skip, 4)
 		ts.Require().NoError(err)
 		ts.Require().Len(targets, expected)
 		for i, h := range targets {
@@ -227,59 +232,173 @@ func (ts *SetupTestSuite) TestSuccess_setupHeadersForUpdate_withHFBoundary() {
 
 }
 
+func (ts *SetupTestSuite) TestSuccess_streamHeadersForUpdate() {
+	target, err := newETHHeader(&types2.Header{Number: big.NewInt(int64(10*skip + 1))})
+	ts.Require().NoError(err)
+	latestFinalizedHeader := &Header{Headers: []*ETHHeader{target}}
+	var queried []uint64
+	neighborFn := func(_ context.Context, height uint64, _ uint64) (core.Header, error) {
+		queried = append(queried, height)
+		h, e := newETHHeader(&types2.Header{Number: big.NewInt(int64(height))})
+		return &Header{Headers: []*ETHHeader{h}}, e
+	}
+	headerFn := func(_ context.Context, height uint64) (*types2.Header, error) {
+		return &types2.Header{Number: big.NewInt(int64(height)), Extra: epochHeader().Extra}, nil
+	}
+	stream := func(ctx context.Context, yield func(*Header) error) error {
+		queried = nil
+		return streamHeadersForUpdate(ctx, neighborFn, headerFn, clienttypes.NewHeight(0, 0), latestFinalizedHeader, clienttypes.NewHeight(0, 100000), forkSpecsAfterMaxwell, skip, 1, yield)
+	}
+
+	// The header is yielded before the next one is queried
+	var yielded []uint64
+	err = stream(context.Background(), func(h *Header) error {
+		yielded = append(yielded, h.GetHeight().GetRevisionHeight())
+		ts.Require().Equal(len(yielded), len(queried))
+		if len(yielded) == 3 {
+			return errStopStream
+		}
+		return nil
+	})
+	ts.Require().NoError(err)
+	ts.Require().Equal([]uint64{skip, 2 * skip, 3 * skip}, yielded)
+	ts.Require().Len(queried, 3)
+
+	// The error of yield is returned as is
+	err = stream(context.Background(), func(h *Header) error {
+		return ErrHeaderTooLarge
+	})
+	ts.Require().ErrorIs(err, ErrHeaderTooLarge)
+	ts.Require().Len(queried, 1)
+
+	// No header is queried after the context is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	err = stream(ctx, func(h *Header) error {
+		cancel()
+		return nil
+	})
+	ts.Require().ErrorIs(err, context.Canceled)
+	ts.Require().Len(queried, 1)
+}
+
+func (ts *SetupTestSuite) Test_queryConcurrently() {
+	heights := []uint64{1000, 2000, 3000, 4000, 5000, 6000}
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	query := func(stopAt uint64) func(context.Context, uint64) (core.Header, error) {
+		return func(_ context.Context, height uint64) (core.Header, error) {
+			mu.Lock()
+			inFlight++
+			maxInFlight = max(maxInFlight, inFlight)
+			mu.Unlock()
+			// The later heights return first
+			time.Sleep(time.Duration(10000-height) * time.Microsecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+			if height == stopAt {
+				return nil, nil
+			}
+			h, err := newETHHeader(&types2.Header{Number: big.NewInt(int64(height))})
+			return &Header{Headers: []*ETHHeader{h}}, err
+		}
+	}
+
+	for _, concurrency := range []uint32{0, 1, 3} {
+		maxInFlight = 0
+		var yielded []uint64
+		completed, err := queryConcurrently(context.Background(), heights, concurrency, query(0), func(height uint64, h core.Header) (bool, error) {
+			ts.Require().Equal(height, h.GetHeight().GetRevisionHeight())
+			yielded = append(yielded, height)
+			return true, nil
+		})
+		ts.Require().NoError(err)
+		ts.Require().True(completed)
+		ts.Require().Equal(heights, yielded)
+		ts.Require().LessOrEqual(maxInFlight, int(max(concurrency, 1)))
+	}
+
+	// Stop at the first nil result
+	var yielded []uint64
+	completed, err := queryConcurrently(context.Background(), heights, 3, query(3000), func(height uint64, h core.Header) (bool, error) {
+		yielded = append(yielded, height)
+		return h != nil, nil
+	})
+	ts.Require().NoError(err)
+	ts.Require().False(completed)
+	ts.Require().Equal([]uint64{1000, 2000, 3000}, yielded)
+
+	// The error of the query is returned in order
+	completed, err = queryConcurrently(context.Background(), heights, 3, func(_ context.Context, height uint64) (core.Header, error) {
+		if height >= 2000 {
+			return nil, fmt.Errorf("failed at %d", height)
+		}
+		return query(0)(context.Background(), height)
+	}, func(uint64, core.Header) (bool, error) {
+		return true, nil
+	})
+	ts.Require().False(completed)
+	ts.Require().EqualError(err, "failed at 2000")
+}
+
 func (ts *SetupTestSuite) Test_makeSubmittingHeights() {
 	rq := ts.Require()
 	msec := uint64(0)
-	rq.Len(makeSubmittingHeights(10, 1, 11, nil, 0), 0)
-	rq.Len(makeSubmittingHeights(10, 1, 11, &msec, 11), 0)
-	rq.Len(makeSubmittingHeights(10, 1, 11, &msec, 9), 1)
-	rq.Len(makeSubmittingHeights(10, 9, 11, &msec, 9), 0)
+	rq.Len(makeSubmittingHeights(10, 1, 11, nil, 0, skip), 0)
+	rq.Len(makeSubmittingHeights(10, 1, 11, &msec, 11, skip), 0)
+	rq.Len(makeSubmittingHeights(10, 1, 11, &msec, 9, skip), 1)
+	rq.Len(makeSubmittingHeights(10, 9, 11, &msec, 9, skip), 0)
 	rq.Equal(
 		[]uint64{skip - 1, skip, 2 * skip, 3 * skip, 4 * skip, 5 * skip},
-		makeSubmittingHeights(5*skip+1, 0, skip, &msec, skip-1),
+		makeSubmittingHeights(5*skip+1, 0, skip, &msec, skip-1, skip),
 	)
 	rq.Equal(
 		[]uint64{skip, 2 * skip, 3 * skip, 4 * skip, 5 * skip},
-		makeSubmittingHeights(5*skip+1, skip-1, skip, &msec, skip-1),
+		makeSubmittingHeights(5*skip+1, skip-1, skip, &msec, skip-1, skip),
 	)
 	rq.Equal(
 		[]uint64{skip, 2 * skip, 3 * skip, 4 * skip, 5 * skip},
-		makeSubmittingHeights(5*skip+1, 0, skip, &msec, skip),
+		makeSubmittingHeights(5*skip+1, 0, skip, &msec, skip, skip),
 	)
 	rq.Equal(
 		[]uint64{skip, skip + 1, 2 * skip, 3 * skip, 4 * skip, 5 * skip},
-		makeSubmittingHeights(5*skip+1, 0, skip, &msec, skip+1),
+		makeSubmittingHeights(5*skip+1, 0, skip, &msec, skip+1, skip),
 	)
 	rq.Equal(
 		[]uint64{skip, 2 * skip, 3 * skip, 4 * skip, 5 * skip},
-		makeSubmittingHeights(5*skip+1, 0, skip, nil, skip+1),
+		makeSubmittingHeights(5*skip+1, 0, skip, nil, skip+1, skip),
 	)
 	rq.Equal(
 		[]uint64{skip, 2 * skip, 2*skip + 1, 3 * skip, 4 * skip, 5 * skip},
-		makeSubmittingHeights(5*skip+1, 0, skip, &msec, 2*skip+1),
+		makeSubmittingHeights(5*skip+1, 0, skip, &msec, 2*skip+1, skip),
 	)
 	rq.Equal(
 		[]uint64{skip, 2 * skip, 3 * skip, 3*skip + 1, 4 * skip, 5 * skip},
-		makeSubmittingHeights(5*skip+1, 0, skip, &msec, 3*skip+1),
+		makeSubmittingHeights(5*skip+1, 0, skip, &msec, 3*skip+1, skip),
 	)
 	rq.Equal(
 		[]uint64{skip, 2 * skip, 3 * skip, 4 * skip, 4*skip + 1, 5 * skip},
-		makeSubmittingHeights(5*skip+1, 0, skip, &msec, 4*skip+1),
+		makeSubmittingHeights(5*skip+1, 0, skip, &msec, 4*skip+1, skip),
 	)
 	rq.Equal(
 		[]uint64{skip, 2 * skip, 3 * skip, 4 * skip, 5 * skip},
-		makeSubmittingHeights(5*skip+1, 0, skip, nil, 4*skip+1),
+		makeSubmittingHeights(5*skip+1, 0, skip, nil, 4*skip+1, skip),
 	)
 	rq.Equal(
 		[]uint64{skip, 2 * skip, 3 * skip, 4 * skip, 5 * skip},
-		makeSubmittingHeights(5*skip+1, 0, skip, &msec, 5*skip+1),
+		makeSubmittingHeights(5*skip+1, 0, skip, &msec, 5*skip+1, skip),
 	)
 	rq.Equal(
 		[]uint64{skip, 2 * skip, 3 * skip, 4 * skip, 5 * skip, 5*skip + 1},
-		makeSubmittingHeights(5*skip+2, 0, skip, &msec, 5*skip+1),
+		makeSubmittingHeights(5*skip+2, 0, skip, &msec, 5*skip+1, skip),
 	)
 	rq.Equal(
 		[]uint64{skip, 2 * skip, 3 * skip, 4 * skip, 5 * skip},
-		makeSubmittingHeights(5*skip+2, 0, skip, nil, 5*skip+1),
+		makeSubmittingHeights(5*skip+2, 0, skip, nil, 5*skip+1, skip),
+	)
+	// interval shorter than the epoch length
+	rq.Equal(
+		[]uint64{skip / 2, skip, 3 * skip / 2, 2 * skip},
+		makeSubmittingHeights(2*skip+1, 0, skip/2, nil, 0, skip/2),
 	)
 }
@@ -0,0 +1,228 @@
+package module
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/bits"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// UpdateSimulation is the result of SimulateUpdate.
+type UpdateSimulation struct {
+	ClientID      string             `json:"client_id"`
+	TrustedHeight clienttypes.Height `json:"trusted_height"`
+	TargetHeight  clienttypes.Height `json:"target_height"`
+	// Error is the reason why the counterparty client would reject the update
+	Error   string             `json:"error,omitempty"`
+	Headers []*SimulatedHeader `json:"headers"`
+	// TotalMsgSize is the sum of the sizes of MsgUpdateClient to be submitted
+	TotalMsgSize int `json:"total_msg_size"`
+	// Batches is the number of the updates to submit all the headers within the UpdateBudget
+	Batches int `json:"batches"`
+}
+
+// SimulatedHeader is the result of the local verification of a header to be submitted.
+type SimulatedHeader struct {
+	Height        clienttypes.Height `json:"height"`
+	TrustedHeight clienttypes.Height `json:"trusted_height"`
+	// ETHHeaders is the number of the ETH headers including the ones proving the finality
+	ETHHeaders   int    `json:"eth_headers"`
+	HeaderSize   int    `json:"header_size"`
+	EstimatedGas uint64 `json:"estimated_gas,omitempty"`
+	MsgSize      int    `json:"msg_size"`
+	Error        string `json:"error,omitempty"`
+}
+
+// Succeeded returns true if every header is expected to be accepted by the counterparty client.
+func (s *UpdateSimulation) Succeeded() bool {
+	if s.Error != "" {
+		return false
+	}
+	for _, h := range s.Headers {
+		if h.Error != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// SimulateUpdate does what SetupHeadersForUpdate does without submitting anything.
+// Each planned header is verified against the validator hashes of the consensus state trusted by the counterparty client
+// and the finality rules, so that the result tells whether the update will succeed and the size of the messages.
+func (pr *Prover) SimulateUpdate(ctx context.Context, counterparty core.FinalityAwareChain, latestFinalizedHeader core.Header) (*UpdateSimulation, error) {
+	header := latestFinalizedHeader.(*Header)
+	_, cs, cons, err := pr.queryCounterpartyClient(ctx, counterparty)
+	if err != nil {
+		return nil, err
+	}
+	trusted, ok := cons.(*ConsensusState)
+	if !ok {
+		return nil, fmt.Errorf("unexpected consensus state type: %T", cons)
+	}
+	signer, err := counterparty.GetAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	simulation := &UpdateSimulation{
+		ClientID:      counterparty.Path().ClientID,
+		TrustedHeight: pr.newHeight(cs.GetLatestHeight().GetRevisionHeight()),
+		TargetHeight:  pr.newHeight(header.GetHeight().GetRevisionHeight()),
+	}
+	if err = pr.checkTrustedState(cs, cons, header, time.Now()); err != nil {
+		simulation.Error = err.Error()
+		return simulation, nil
+	}
+	headers, err := pr.SetupHeadersForUpdateByLatestHeight(ctx, cs.GetLatestHeight(), header)
+	if err != nil {
+		simulation.Error = err.Error()
+		return simulation, nil
+	}
+
+	// The headers are batched in the same way as streamHeadersForUpdate, which submits only the first batch in an update.
+	batch := &batchBuilder{budget: pr.config.GetUpdateBudget()}
+	if len(headers) > 0 {
+		simulation.Batches = 1
+	}
+	trustedHeight := simulation.TrustedHeight
+	for _, h := range headers {
+		h := h.(*Header)
+		ok, err := batch.add(h)
+		if err == nil && !ok {
+			batch.reset()
+			simulation.Batches++
+			_, err = batch.add(h)
+		}
+		if err != nil {
+			simulation.Error = err.Error()
+		}
+		simulated := &SimulatedHeader{
+			Height:       pr.newHeight(h.GetHeight().GetRevisionHeight()),
+			ETHHeaders:   len(h.Headers),
+			HeaderSize:   h.Size(),
+			EstimatedGas: pr.config.GetUpdateBudget().EstimateGas(h),
+		}
+		if h.TrustedHeight != nil {
+			simulated.TrustedHeight = *h.TrustedHeight
+		}
+		msg, err := clienttypes.NewMsgUpdateClient(simulation.ClientID, h, signer.String())
+		if err != nil {
+			return nil, err
+		}
+		simulated.MsgSize = msg.Size()
+		simulation.TotalMsgSize += simulated.MsgSize
+		simulation.Headers = append(simulation.Headers, simulated)
+
+		// The following headers are verified against the consensus state the invalid header would have stored.
+		if err = pr.verifyHeader(ctx, trustedHeight, trusted, h); err != nil {
+			simulated.Error = err.Error()
+		}
+		trustedHeight = simulated.Height
+		trusted = &ConsensusState{
+			CurrentValidatorsHash:  makeEpochHash(h.CurrentValidators, uint8(h.CurrentTurnLength)),
+			PreviousValidatorsHash: makeEpochHash(h.PreviousValidators, uint8(h.PreviousTurnLength)),
+		}
+	}
+	return simulation, nil
+}
+
+// verifyHeader verifies the header in the same way as the Parlia light client except for the BLS signatures.
+func (pr *Prover) verifyHeader(ctx context.Context, trustedHeight clienttypes.Height, trusted *ConsensusState, h *Header) error {
+	if err := h.ValidateBasic(); err != nil {
+		return err
+	}
+	if h.TrustedHeight == nil || !h.TrustedHeight.EQ(trustedHeight) {
+		return fmt.Errorf("unexpected trusted height: expected=%s, actual=%v", trustedHeight, h.TrustedHeight)
+	}
+	if !h.GetHeight().GT(trustedHeight) {
+		return fmt.Errorf("height %s is not greater than the trusted height %s", h.GetHeight(), trustedHeight)
+	}
+	if err := verifyValidatorsHash(trusted, h); err != nil {
+		return err
+	}
+	ethHeaders, err := h.decodeEthHeaders()
+	if err != nil {
+		return err
+	}
+	// The validator sets are queried once for the ETH headers in the same epoch
+	var epoch *epochValidators
+	return verifyFinality(ethHeaders, func(height uint64) (Validators, error) {
+		if epoch == nil || !epoch.contains(height) {
+			if epoch, err = queryEpochValidators(ctx, pr.chain.Header, height, pr.getForkParameters()); err != nil {
+				return nil, err
+			}
+		}
+		return epoch.Signers(height), nil
+	})
+}
+
+// verifyValidatorsHash accepts the header in the same epoch as the trusted consensus state
+// or in the epoch next to it.
+func verifyValidatorsHash(trusted *ConsensusState, h *Header) error {
+	current := makeEpochHash(h.CurrentValidators, uint8(h.CurrentTurnLength))
+	previous := makeEpochHash(h.PreviousValidators, uint8(h.PreviousTurnLength))
+	if bytes.Equal(current, trusted.CurrentValidatorsHash) && bytes.Equal(previous, trusted.PreviousValidatorsHash) {
+		return nil
+	}
+	if bytes.Equal(previous, trusted.CurrentValidatorsHash) {
+		return nil
+	}
+	return fmt.Errorf("validators are not trusted: current=%x, previous=%x, trustedCurrent=%x, trustedPrevious=%x",
+		current, previous, trusted.CurrentValidatorsHash, trusted.PreviousValidatorsHash)
+}
+
+// verifyFinality checks that the first header is justified by a descendant and the justification is justified by
+// another descendant, with the votes of more than 2/3 of the signers.
+func verifyFinality(headers []*types.Header, signers func(height uint64) (Validators, error)) error {
+	target := headers[0]
+	for i := 1; i < len(headers); i++ {
+		child := headers[i]
+		childVote, err := getVoteAttestationFromHeader(child)
+		if err != nil {
+			return err
+		}
+		if childVote == nil || childVote.Data.TargetNumber != target.Number.Uint64() || childVote.Data.TargetHash != target.Hash() {
+			continue
+		}
+		if err = verifyVoteQuorum(child, childVote, signers); err != nil {
+			return err
+		}
+		for _, grandChild := range headers[i+1:] {
+			grandChildVote, err := getVoteAttestationFromHeader(grandChild)
+			if err != nil {
+				return err
+			}
+			if grandChildVote == nil ||
+				grandChildVote.Data.SourceNumber != childVote.Data.TargetNumber ||
+				grandChildVote.Data.SourceHash != childVote.Data.TargetHash ||
+				grandChildVote.Data.TargetNumber != child.Number.Uint64() ||
+				grandChildVote.Data.TargetHash != child.Hash() {
+				continue
+			}
+			return verifyVoteQuorum(grandChild, grandChildVote, signers)
+		}
+		return fmt.Errorf("no descendant justifies the child: target=%d, child=%d", target.Number.Uint64(), child.Number.Uint64())
+	}
+	return fmt.Errorf("no descendant justifies the target: target=%d", target.Number.Uint64())
+}
+
+func verifyVoteQuorum(header *types.Header, vote *VoteAttestation, signers func(height uint64) (Validators, error)) error {
+	// The votes are cast by the validator set of the target, which is the parent of the header as Parlia verifies
+	validators, err := signers(vote.Data.TargetNumber)
+	if err != nil {
+		return err
+	}
+	voters := bits.OnesCount64(vote.VoteAddressSet)
+	if vote.VoteAddressSet>>len(validators) != 0 {
+		return fmt.Errorf("vote address set exceeds the validators: number=%d, set=%b, validators=%d", header.Number.Uint64(), vote.VoteAddressSet, len(validators))
+	}
+	if required := (len(validators)*2 + 2) / 3; voters < required {
+		return fmt.Errorf("insufficient voters: number=%d, voters=%d, required=%d", header.Number.Uint64(), voters, required)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package module
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type SimulateTestSuite struct {
+	suite.Suite
+}
+
+func TestSimulateTestSuite(t *testing.T) {
+	suite.Run(t, new(SimulateTestSuite))
+}
+
+func (ts *SimulateTestSuite) TestVerifyValidatorsHash() {
+	current := Validators{{1}, {2}}
+	previous := Validators{{3}, {4}}
+	trusted := &ConsensusState{
+		CurrentValidatorsHash:  makeEpochHash(current, 1),
+		PreviousValidatorsHash: makeEpochHash(previous, 1),
+	}
+	// same epoch
+	ts.Require().NoError(verifyValidatorsHash(trusted, &Header{CurrentValidators: current, CurrentTurnLength: 1, PreviousValidators: previous, PreviousTurnLength: 1}))
+	// next epoch
+	ts.Require().NoError(verifyValidatorsHash(trusted, &Header{CurrentValidators: Validators{{5}}, CurrentTurnLength: 4, PreviousValidators: current, PreviousTurnLength: 1}))
+	// turn length is a part of the hash
+	ts.Require().Error(verifyValidatorsHash(trusted, &Header{CurrentValidators: Validators{{5}}, CurrentTurnLength: 4, PreviousValidators: current, PreviousTurnLength: 4}))
+	// two epochs later
+	ts.Require().Error(verifyValidatorsHash(trusted, &Header{CurrentValidators: Validators{{5}}, CurrentTurnLength: 1, PreviousValidators: Validators{{6}}, PreviousTurnLength: 1}))
+}
+
+func (ts *SimulateTestSuite) TestVerifyFinality() {
+	validators, _, err := extractValidatorSetAndTurnLength(epochHeader())
+	ts.Require().NoError(err)
+	var heights []uint64
+	signers := func(height uint64) (Validators, error) {
+		heights = append(heights, height)
+		return validators, nil
+	}
+	headers := []*types.Header{epochHeader(), epochHeaderPlus1(), epochHeaderPlus2()}
+	ts.Require().NoError(verifyFinality(headers, signers))
+	// the votes are verified against the validator sets of their targets
+	ts.Require().Equal([]uint64{1000, 1001}, heights)
+
+	// no grand child
+	ts.Require().ErrorContains(verifyFinality(headers[:2], signers), "justifies the child")
+	// no child
+	ts.Require().ErrorContains(verifyFinality(headers[1:2], signers), "justifies the target")
+
+	// insufficient voters
+	ts.Require().ErrorContains(verifyFinality(headers, func(height uint64) (Validators, error) {
+		return append(append(Validators{}, validators...), validators...), nil
+	}), "insufficient voters")
+	// vote address set exceeds the validators
+	ts.Require().ErrorContains(verifyFinality(headers, func(height uint64) (Validators, error) {
+		return validators[:1], nil
+	}), "exceeds")
+	ts.Require().Error(verifyFinality(headers, func(height uint64) (Validators, error) {
+		return nil, fmt.Errorf("error")
+	}))
+}
+
+func (ts *SimulateTestSuite) TestSucceeded() {
+	simulation := &UpdateSimulation{Headers: []*SimulatedHeader{{}, {}}}
+	ts.Require().True(simulation.Succeeded())
+	simulation.Headers[1].Error = "invalid"
+	ts.Require().False(simulation.Succeeded())
+	ts.Require().False((&UpdateSimulation{Error: "expired"}).Succeeded())
+}
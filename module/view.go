@@ -0,0 +1,276 @@
+package module
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+
+	"github.com/cosmos/gogoproto/proto"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// The views are human-readable representations of the Parlia messages for logs and CLI output.
+// Unlike the protobuf JSON, RLP encoded headers and proofs are decoded.
+
+type HeaderView struct {
+	Headers            []*ETHHeaderView    `json:"headers"`
+	TrustedHeight      *clienttypes.Height `json:"trusted_height,omitempty"`
+	CurrentValidators  []ValidatorView     `json:"current_validators"`
+	CurrentTurnLength  uint32              `json:"current_turn_length"`
+	PreviousValidators []ValidatorView     `json:"previous_validators"`
+	PreviousTurnLength uint32              `json:"previous_turn_length"`
+}
+
+type ETHHeaderView struct {
+	Number     uint64      `json:"number"`
+	Hash       common.Hash `json:"hash"`
+	ParentHash common.Hash `json:"parent_hash"`
+	// Timestamp is the block time in milliseconds
+	Timestamp uint64 `json:"timestamp"`
+	// Signer is the coinbase, which Parlia requires to be the validator sealing the block
+	Signer          common.Address       `json:"signer"`
+	StateRoot       common.Hash          `json:"state_root"`
+	GasLimit        uint64               `json:"gas_limit"`
+	GasUsed         uint64               `json:"gas_used"`
+	VoteAttestation *VoteAttestationView `json:"vote_attestation,omitempty"`
+	// Validators and TurnLength are set only for epoch blocks
+	Validators []ValidatorView `json:"validators,omitempty"`
+	TurnLength *uint8          `json:"turn_length,omitempty"`
+}
+
+type VoteAttestationView struct {
+	VoteAddressSet uint64        `json:"vote_address_set"`
+	Voters         int           `json:"voters"`
+	AggSignature   hexutil.Bytes `json:"agg_signature"`
+	SourceNumber   uint64        `json:"source_number"`
+	SourceHash     common.Hash   `json:"source_hash"`
+	TargetNumber   uint64        `json:"target_number"`
+	TargetHash     common.Hash   `json:"target_hash"`
+}
+
+type ValidatorView struct {
+	Address common.Address `json:"address"`
+	BLSKey  hexutil.Bytes  `json:"bls_key"`
+}
+
+type ClientStateView struct {
+	ChainId            uint64              `json:"chain_id"`
+	IbcStoreAddress    common.Address      `json:"ibc_store_address"`
+	IbcCommitmentsSlot common.Hash         `json:"ibc_commitments_slot"`
+	LatestHeight       *clienttypes.Height `json:"latest_height"`
+	TrustingPeriod     string              `json:"trusting_period"`
+	MaxClockDrift      string              `json:"max_clock_drift"`
+	Frozen             bool                `json:"frozen"`
+	ForkSpecs          []*ForkSpec         `json:"fork_specs"`
+}
+
+type ConsensusStateView struct {
+	StateRoot              common.Hash `json:"state_root"`
+	Timestamp              uint64      `json:"timestamp"`
+	CurrentValidatorsHash  common.Hash `json:"current_validators_hash"`
+	PreviousValidatorsHash common.Hash `json:"previous_validators_hash"`
+}
+
+type MisbehaviourView struct {
+	ClientId string      `json:"client_id"`
+	Header1  *HeaderView `json:"header_1"`
+	Header2  *HeaderView `json:"header_2"`
+}
+
+type ProveStateView struct {
+	AccountProof    []TrieNodeView `json:"account_proof"`
+	CommitmentProof []TrieNodeView `json:"commitment_proof"`
+}
+
+type TrieNodeView struct {
+	Hash common.Hash `json:"hash"`
+	// Type is branch, extension or leaf
+	Type  string          `json:"type"`
+	Items []hexutil.Bytes `json:"items"`
+}
+
+// NewView returns the view of the Parlia message.
+func NewView(msg proto.Message) (interface{}, error) {
+	switch m := msg.(type) {
+	case *Header:
+		return NewHeaderView(m)
+	case *ClientState:
+		return NewClientStateView(m), nil
+	case *ConsensusState:
+		return NewConsensusStateView(m), nil
+	case *Misbehaviour:
+		return NewMisbehaviourView(m)
+	case *ProveState:
+		return NewProveStateView(m)
+	}
+	return nil, fmt.Errorf("unsupported message: %T", msg)
+}
+
+// MarshalViewJSON returns the indented JSON of the view of the Parlia message.
+func MarshalViewJSON(msg proto.Message) ([]byte, error) {
+	view, err := NewView(msg)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(view, "", "  ")
+}
+
+func NewHeaderView(h *Header) (*HeaderView, error) {
+	view := &HeaderView{
+		TrustedHeight:      h.TrustedHeight,
+		CurrentValidators:  NewValidatorViews(h.CurrentValidators),
+		CurrentTurnLength:  h.CurrentTurnLength,
+		PreviousValidators: NewValidatorViews(h.PreviousValidators),
+		PreviousTurnLength: h.PreviousTurnLength,
+	}
+	for i, e := range h.Headers {
+		ethHeaderView, err := NewETHHeaderView(e)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode header: index=%d, %w", i, err)
+		}
+		view.Headers = append(view.Headers, ethHeaderView)
+	}
+	return view, nil
+}
+
+func NewETHHeaderView(e *ETHHeader) (*ETHHeaderView, error) {
+	header, err := e.decode()
+	if err != nil {
+		return nil, err
+	}
+	view := &ETHHeaderView{
+		Number:     header.Number.Uint64(),
+		Hash:       header.Hash(),
+		ParentHash: header.ParentHash,
+		Timestamp:  MilliTimestamp(header),
+		Signer:     header.Coinbase,
+		StateRoot:  header.Root,
+		GasLimit:   header.GasLimit,
+		GasUsed:    header.GasUsed,
+	}
+	if validators, turnLength, err := extractValidatorSetAndTurnLength(header); err == nil {
+		view.Validators = NewValidatorViews(validators)
+		view.TurnLength = &turnLength
+	}
+	vote, err := getVoteAttestationFromHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	if vote != nil && vote.Data != nil {
+		view.VoteAttestation = &VoteAttestationView{
+			VoteAddressSet: vote.VoteAddressSet,
+			Voters:         bits.OnesCount64(vote.VoteAddressSet),
+			AggSignature:   vote.AggSignature[:],
+			SourceNumber:   vote.Data.SourceNumber,
+			SourceHash:     vote.Data.SourceHash,
+			TargetNumber:   vote.Data.TargetNumber,
+			TargetHash:     vote.Data.TargetHash,
+		}
+	}
+	return view, nil
+}
+
+// NewValidatorViews splits each validator into the address and the BLS public key.
+// Malformed entries are shown as the BLS key with an empty address.
+func NewValidatorViews(validators Validators) []ValidatorView {
+	views := make([]ValidatorView, 0, len(validators))
+	for _, v := range validators {
+		if len(v) != validatorBytesLength {
+			views = append(views, ValidatorView{BLSKey: v})
+			continue
+		}
+		views = append(views, ValidatorView{
+			Address: common.BytesToAddress(v[:common.AddressLength]),
+			BLSKey:  v[common.AddressLength:],
+		})
+	}
+	return views
+}
+
+func NewClientStateView(cs *ClientState) *ClientStateView {
+	return &ClientStateView{
+		ChainId:            cs.ChainId,
+		IbcStoreAddress:    common.BytesToAddress(cs.IbcStoreAddress),
+		IbcCommitmentsSlot: common.BytesToHash(cs.IbcCommitmentsSlot),
+		LatestHeight:       cs.LatestHeight,
+		TrustingPeriod:     cs.TrustingPeriod.String(),
+		MaxClockDrift:      cs.MaxClockDrift.String(),
+		Frozen:             cs.Frozen,
+		ForkSpecs:          cs.ForkSpecs,
+	}
+}
+
+func NewConsensusStateView(cs *ConsensusState) *ConsensusStateView {
+	return &ConsensusStateView{
+		StateRoot:              common.BytesToHash(cs.StateRoot),
+		Timestamp:              cs.Timestamp,
+		CurrentValidatorsHash:  common.BytesToHash(cs.CurrentValidatorsHash),
+		PreviousValidatorsHash: common.BytesToHash(cs.PreviousValidatorsHash),
+	}
+}
+
+func NewMisbehaviourView(m *Misbehaviour) (*MisbehaviourView, error) {
+	view := &MisbehaviourView{ClientId: m.ClientId}
+	var err error
+	if m.Header_1 != nil {
+		if view.Header1, err = NewHeaderView(m.Header_1); err != nil {
+			return nil, err
+		}
+	}
+	if m.Header_2 != nil {
+		if view.Header2, err = NewHeaderView(m.Header_2); err != nil {
+			return nil, err
+		}
+	}
+	return view, nil
+}
+
+func NewProveStateView(p *ProveState) (*ProveStateView, error) {
+	accountProof, err := newTrieNodeViews(p.AccountProof)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode account proof: %w", err)
+	}
+	commitmentProof, err := newTrieNodeViews(p.CommitmentProof)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode commitment proof: %w", err)
+	}
+	return &ProveStateView{AccountProof: accountProof, CommitmentProof: commitmentProof}, nil
+}
+
+func newTrieNodeViews(proof []byte) ([]TrieNodeView, error) {
+	if len(proof) == 0 {
+		return nil, nil
+	}
+	nodes, err := decodeAccountProof(proof)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]TrieNodeView, 0, len(nodes))
+	for _, node := range nodes {
+		var items [][]byte
+		if err = rlp.DecodeBytes(node, &items); err != nil {
+			return nil, err
+		}
+		view := TrieNodeView{Hash: crypto.Keccak256Hash(node), Type: trieNodeType(items)}
+		for _, item := range items {
+			view.Items = append(view.Items, item)
+		}
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+func trieNodeType(items [][]byte) string {
+	if len(items) == 17 {
+		return "branch"
+	}
+	// The first nibble of the compact encoded path is 2 or 3 for a leaf
+	if len(items) == 2 && len(items[0]) > 0 && items[0][0]>>4 >= 2 {
+		return "leaf"
+	}
+	return "extension"
+}
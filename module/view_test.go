@@ -0,0 +1,103 @@
+package module
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/suite"
+)
+
+type ViewTestSuite struct {
+	suite.Suite
+}
+
+func TestViewTestSuite(t *testing.T) {
+	suite.Run(t, new(ViewTestSuite))
+}
+
+func (ts *ViewTestSuite) TestHeaderView() {
+	header := epochHeader()
+	ethHeader, err := newETHHeader(header)
+	ts.Require().NoError(err)
+	trustedHeight := clienttypes.NewHeight(0, header.Number.Uint64()-1)
+	validator := make([]byte, validatorBytesLength)
+	validator[0] = 1
+	validator[validatorBytesLength-1] = 2
+
+	view, err := NewHeaderView(&Header{
+		Headers:           []*ETHHeader{ethHeader},
+		TrustedHeight:     &trustedHeight,
+		CurrentValidators: [][]byte{validator},
+	})
+	ts.Require().NoError(err)
+	ts.Require().Len(view.Headers, 1)
+	ts.Require().Equal(header.Number.Uint64(), view.Headers[0].Number)
+	ts.Require().Equal(header.Hash(), view.Headers[0].Hash)
+	ts.Require().Equal(MilliTimestamp(header), view.Headers[0].Timestamp)
+	ts.Require().Equal(header.Coinbase, view.Headers[0].Signer)
+	ts.Require().NotNil(view.Headers[0].VoteAttestation)
+	ts.Require().Equal(uint64(15), view.Headers[0].VoteAttestation.VoteAddressSet)
+	ts.Require().Equal(4, view.Headers[0].VoteAttestation.Voters)
+	ts.Require().Len(view.Headers[0].Validators, 4)
+	ts.Require().Equal(common.BytesToAddress(validator[:common.AddressLength]), view.CurrentValidators[0].Address)
+	ts.Require().Equal(validator[common.AddressLength:], []byte(view.CurrentValidators[0].BLSKey))
+
+	bz, err := MarshalViewJSON(&Header{Headers: []*ETHHeader{ethHeader}})
+	ts.Require().NoError(err)
+	var decoded map[string]interface{}
+	ts.Require().NoError(json.Unmarshal(bz, &decoded))
+
+	_, err = NewHeaderView(&Header{Headers: []*ETHHeader{{Header: []byte{0x01}}}})
+	ts.Require().Error(err)
+}
+
+func (ts *ViewTestSuite) TestStateViews() {
+	latestHeight := clienttypes.NewHeight(0, 100)
+	cs := NewClientStateView(&ClientState{
+		ChainId:            56,
+		IbcStoreAddress:    common.HexToAddress("0xaa43d337145E8930d01cb4E60Abf6595C692921E").Bytes(),
+		IbcCommitmentsSlot: IBCCommitmentsSlot[:],
+		LatestHeight:       &latestHeight,
+		TrustingPeriod:     100 * time.Second,
+	})
+	ts.Require().Equal(common.HexToAddress("0xaa43d337145E8930d01cb4E60Abf6595C692921E"), cs.IbcStoreAddress)
+	ts.Require().Equal(IBCCommitmentsSlot, cs.IbcCommitmentsSlot)
+	ts.Require().Equal("1m40s", cs.TrustingPeriod)
+
+	cons := NewConsensusStateView(&ConsensusState{StateRoot: common.HexToHash("0x01").Bytes(), Timestamp: 1})
+	ts.Require().Equal(common.HexToHash("0x01"), cons.StateRoot)
+}
+
+func (ts *ViewTestSuite) TestProveStateView() {
+	leaf, err := rlp.EncodeToBytes([][]byte{{0x20, 0x01}, {0x02}})
+	ts.Require().NoError(err)
+	branchItems := make([][]byte, 17)
+	branch, err := rlp.EncodeToBytes(branchItems)
+	ts.Require().NoError(err)
+	proof, err := rlp.EncodeToBytes([]rlp.RawValue{branch, leaf})
+	ts.Require().NoError(err)
+
+	view, err := NewProveStateView(&ProveState{AccountProof: proof})
+	ts.Require().NoError(err)
+	ts.Require().Len(view.AccountProof, 2)
+	ts.Require().Equal("branch", view.AccountProof[0].Type)
+	ts.Require().Equal("leaf", view.AccountProof[1].Type)
+	ts.Require().Empty(view.CommitmentProof)
+
+	_, err = NewProveStateView(&ProveState{AccountProof: []byte{0x01}})
+	ts.Require().Error(err)
+
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(2)}
+	ethHeader, err := newETHHeader(header)
+	ts.Require().NoError(err)
+	view2, err := NewView(&Misbehaviour{ClientId: "xx-parlia-0", Header_1: &Header{Headers: []*ETHHeader{ethHeader}}})
+	ts.Require().NoError(err)
+	ts.Require().Equal("xx-parlia-0", view2.(*MisbehaviourView).ClientId)
+	ts.Require().Nil(view2.(*MisbehaviourView).Header2)
+}
@@ -0,0 +1,187 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type VoteViolationType string
+
+const (
+	// DoubleVote is two votes of a validator for different targets at the same target number
+	DoubleVote VoteViolationType = "double_vote"
+	// SurroundVote is a vote of a validator whose source and target surround another vote of the validator
+	SurroundVote VoteViolationType = "surround_vote"
+)
+
+// VoteViolation is a report of a validator that signed conflicting fast finality votes.
+type VoteViolation struct {
+	Type      VoteViolationType
+	Validator common.Address
+	BLSKey    BLSPublicKey
+	// Vote1 and Vote2 are the conflicting votes. Vote1 was observed first.
+	Vote1 ObservedVote
+	Vote2 ObservedVote
+}
+
+func (v *VoteViolation) String() string {
+	return fmt.Sprintf("%s: validator=%s, vote1={block=%d, source=%d, target=%d:%s}, vote2={block=%d, source=%d, target=%d:%s}",
+		v.Type, v.Validator,
+		v.Vote1.BlockNumber, v.Vote1.Data.SourceNumber, v.Vote1.Data.TargetNumber, v.Vote1.Data.TargetHash,
+		v.Vote2.BlockNumber, v.Vote2.Data.SourceNumber, v.Vote2.Data.TargetNumber, v.Vote2.Data.TargetHash)
+}
+
+// ObservedVote is a vote of a validator found in the VoteAttestation of a block.
+type ObservedVote struct {
+	// BlockNumber is the number of the block containing the VoteAttestation
+	BlockNumber uint64
+	Data        VoteData
+}
+
+// VoteDetector indexes the votes of each validator found in VoteAttestations and detects
+// double votes and surround votes, which are slashable under BSC fast finality.
+// VoteDetector is not safe for concurrent use.
+type VoteDetector struct {
+	// votes indexes the observed votes by validator and target number
+	votes      map[BLSPublicKey]map[uint64]ObservedVote
+	validators map[BLSPublicKey]common.Address
+	// epoch is the validator sets of the epoch of the last observed header,
+	// so that the headers in the same epoch are resolved without querying the epoch headers again
+	epoch *observedEpoch
+}
+
+type observedEpoch struct {
+	forkSpec   *ForkSpec
+	validators *epochValidators
+}
+
+// contains returns true if the block of `number` under `forkSpec` is produced by the validator sets of the epoch.
+func (e *observedEpoch) contains(number uint64, forkSpec *ForkSpec) bool {
+	return e != nil && proto.Equal(e.forkSpec, forkSpec) && e.validators.contains(number)
+}
+
+func NewVoteDetector() *VoteDetector {
+	return &VoteDetector{
+		votes:      make(map[BLSPublicKey]map[uint64]ObservedVote),
+		validators: make(map[BLSPublicKey]common.Address),
+	}
+}
+
+// ObserveHeader indexes the votes in the VoteAttestation of the header.
+// The voters are resolved against the validator set of the parent as Parlia does,
+// which is not the one producing the header at the checkpoint of an epoch.
+func (d *VoteDetector) ObserveHeader(ctx context.Context, headerFn getHeaderFn, header *types.Header, forkSpecs []*ForkSpec) ([]*VoteViolation, error) {
+	attestation, err := getVoteAttestationFromHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	if attestation == nil {
+		return nil, nil
+	}
+	number := header.Number.Uint64()
+	forkSpec, _, err := FindTargetForkSpec(forkSpecs, number, MilliTimestamp(header))
+	if err != nil {
+		return nil, err
+	}
+	parent := number - 1
+	if !d.epoch.contains(parent, forkSpec) {
+		epoch, err := queryEpochValidators(ctx, headerFn, parent, forkSpecs)
+		if err != nil {
+			return nil, err
+		}
+		d.epoch = &observedEpoch{forkSpec: forkSpec, validators: epoch}
+	}
+	return d.Observe(number, attestation, d.epoch.validators.Signers(parent))
+}
+
+// Observe indexes the votes in `attestation` included in the block `blockNumber` and returns the violations found.
+// `validators` is the validator set whose indexes are referred by VoteAddressSet.
+func (d *VoteDetector) Observe(blockNumber uint64, attestation *VoteAttestation, validators Validators) ([]*VoteViolation, error) {
+	if attestation.Data == nil {
+		return nil, fmt.Errorf("vote data is empty: block=%d", blockNumber)
+	}
+	voters, err := resolveVoters(validators, attestation.VoteAddressSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve voters: block=%d, %w", blockNumber, err)
+	}
+	vote := ObservedVote{BlockNumber: blockNumber, Data: *attestation.Data}
+	var violations []*VoteViolation
+	for _, voter := range voters {
+		var key BLSPublicKey
+		copy(key[:], voter[common.AddressLength:])
+		d.validators[key] = common.BytesToAddress(voter[:common.AddressLength])
+		if violation := d.observe(key, vote); violation != nil {
+			violations = append(violations, violation)
+		}
+	}
+	return violations, nil
+}
+
+func (d *VoteDetector) observe(key BLSPublicKey, vote ObservedVote) *VoteViolation {
+	votes, ok := d.votes[key]
+	if !ok {
+		votes = make(map[uint64]ObservedVote)
+		d.votes[key] = votes
+	}
+	newViolation := func(violationType VoteViolationType, observed ObservedVote) *VoteViolation {
+		return &VoteViolation{Type: violationType, Validator: d.validators[key], BLSKey: key, Vote1: observed, Vote2: vote}
+	}
+
+	target := vote.Data
+	if observed, ok := votes[target.TargetNumber]; ok {
+		if observed.Data.TargetHash != target.TargetHash {
+			return newViolation(DoubleVote, observed)
+		}
+		return nil
+	}
+	for _, observed := range votes {
+		if isSurrounded(observed.Data, target) || isSurrounded(target, observed.Data) {
+			return newViolation(SurroundVote, observed)
+		}
+	}
+	votes[target.TargetNumber] = vote
+	return nil
+}
+
+// Prune removes the votes whose target number is lower than `targetNumber`.
+func (d *VoteDetector) Prune(targetNumber uint64) {
+	for key, votes := range d.votes {
+		for number := range votes {
+			if number < targetNumber {
+				delete(votes, number)
+			}
+		}
+		if len(votes) == 0 {
+			delete(d.votes, key)
+			delete(d.validators, key)
+		}
+	}
+}
+
+// isSurrounded returns true if the range of `outer` strictly surrounds the range of `inner`.
+func isSurrounded(inner, outer VoteData) bool {
+	return outer.SourceNumber < inner.SourceNumber && inner.TargetNumber < outer.TargetNumber
+}
+
+// resolveVoters returns the validators whose indexes are set in VoteAddressSet.
+func resolveVoters(validators Validators, voteAddressSet uint64) (Validators, error) {
+	if bits.Len64(voteAddressSet) > len(validators) {
+		return nil, fmt.Errorf("vote address set %b exceeds the validator set size %d", voteAddressSet, len(validators))
+	}
+	var voters Validators
+	for i, validator := range validators {
+		if voteAddressSet&(1<<uint(i)) == 0 {
+			continue
+		}
+		if len(validator) != validatorBytesLength {
+			return nil, fmt.Errorf("invalid validator bytes length: index=%d, length=%d", i, len(validator))
+		}
+		voters = append(voters, validator)
+	}
+	return voters, nil
+}
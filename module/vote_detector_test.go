@@ -0,0 +1,201 @@
+package module
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/suite"
+)
+
+type VoteDetectorTestSuite struct {
+	suite.Suite
+	validators Validators
+}
+
+func TestVoteDetectorTestSuite(t *testing.T) {
+	suite.Run(t, new(VoteDetectorTestSuite))
+}
+
+func (ts *VoteDetectorTestSuite) SetupTest() {
+	ts.validators = nil
+	for i := 0; i < 4; i++ {
+		validator := make([]byte, validatorBytesLength)
+		validator[0] = byte(i + 1)
+		validator[common.AddressLength] = byte(i + 1)
+		ts.validators = append(ts.validators, validator)
+	}
+}
+
+func (ts *VoteDetectorTestSuite) attestation(voteAddressSet uint64, source, target uint64, targetHash common.Hash) *VoteAttestation {
+	return &VoteAttestation{
+		VoteAddressSet: voteAddressSet,
+		Data: &VoteData{
+			SourceNumber: source,
+			SourceHash:   common.BigToHash(common.Big1),
+			TargetNumber: target,
+			TargetHash:   targetHash,
+		},
+	}
+}
+
+func (ts *VoteDetectorTestSuite) TestResolveVoters() {
+	voters, err := resolveVoters(ts.validators, 0b1010)
+	ts.Require().NoError(err)
+	ts.Require().Equal(Validators{ts.validators[1], ts.validators[3]}, voters)
+
+	_, err = resolveVoters(ts.validators, 0b10000)
+	ts.Require().Error(err)
+}
+
+func (ts *VoteDetectorTestSuite) TestDoubleVote() {
+	detector := NewVoteDetector()
+	violations, err := detector.Observe(11, ts.attestation(0b0011, 9, 10, common.HexToHash("0x01")), ts.validators)
+	ts.Require().NoError(err)
+	ts.Require().Empty(violations)
+
+	// same vote is not a violation
+	violations, err = detector.Observe(11, ts.attestation(0b0001, 9, 10, common.HexToHash("0x01")), ts.validators)
+	ts.Require().NoError(err)
+	ts.Require().Empty(violations)
+
+	// validator 1 votes for another target at the same number
+	violations, err = detector.Observe(11, ts.attestation(0b0110, 9, 10, common.HexToHash("0x02")), ts.validators)
+	ts.Require().NoError(err)
+	ts.Require().Len(violations, 1)
+	ts.Require().Equal(DoubleVote, violations[0].Type)
+	ts.Require().Equal(common.BytesToAddress(ts.validators[1][:common.AddressLength]), violations[0].Validator)
+	ts.Require().Equal(common.HexToHash("0x01"), violations[0].Vote1.Data.TargetHash)
+	ts.Require().Equal(common.HexToHash("0x02"), violations[0].Vote2.Data.TargetHash)
+}
+
+func (ts *VoteDetectorTestSuite) TestSurroundVote() {
+	detector := NewVoteDetector()
+	violations, err := detector.Observe(13, ts.attestation(0b0001, 10, 12, common.HexToHash("0x01")), ts.validators)
+	ts.Require().NoError(err)
+	ts.Require().Empty(violations)
+
+	// adjacent votes are not surrounding
+	violations, err = detector.Observe(14, ts.attestation(0b0001, 12, 13, common.HexToHash("0x02")), ts.validators)
+	ts.Require().NoError(err)
+	ts.Require().Empty(violations)
+
+	// new vote surrounds the observed vote
+	violations, err = detector.Observe(16, ts.attestation(0b0001, 9, 15, common.HexToHash("0x03")), ts.validators)
+	ts.Require().NoError(err)
+	ts.Require().Len(violations, 1)
+	ts.Require().Equal(SurroundVote, violations[0].Type)
+
+	// new vote is surrounded by the observed vote
+	detector = NewVoteDetector()
+	_, err = detector.Observe(16, ts.attestation(0b0001, 9, 15, common.HexToHash("0x03")), ts.validators)
+	ts.Require().NoError(err)
+	violations, err = detector.Observe(13, ts.attestation(0b0001, 10, 12, common.HexToHash("0x01")), ts.validators)
+	ts.Require().NoError(err)
+	ts.Require().Len(violations, 1)
+	ts.Require().Equal(SurroundVote, violations[0].Type)
+}
+
+func (ts *VoteDetectorTestSuite) TestPrune() {
+	detector := NewVoteDetector()
+	_, err := detector.Observe(11, ts.attestation(0b0001, 9, 10, common.HexToHash("0x01")), ts.validators)
+	ts.Require().NoError(err)
+	detector.Prune(11)
+	ts.Require().Empty(detector.votes)
+
+	violations, err := detector.Observe(11, ts.attestation(0b0001, 9, 10, common.HexToHash("0x02")), ts.validators)
+	ts.Require().NoError(err)
+	ts.Require().Empty(violations)
+}
+
+func (ts *VoteDetectorTestSuite) TestSigners() {
+	epoch := &epochValidators{
+		CurrentEpoch:       200,
+		CurrentValidators:  ts.validators[:1],
+		CurrentTurnLength:  1,
+		PreviousValidators: ts.validators,
+		PreviousTurnLength: 1,
+	}
+	// checkpoint is 4/2+1 = 3 blocks after the epoch
+	ts.Require().Equal(ts.validators, epoch.Signers(200))
+	ts.Require().Equal(ts.validators, epoch.Signers(202))
+	ts.Require().Equal(ts.validators[:1], epoch.Signers(203))
+}
+
+func (ts *VoteDetectorTestSuite) TestObserveHeaderInSameEpoch() {
+	queried := 0
+	headerFn := func(_ context.Context, number uint64) (*types.Header, error) {
+		queried++
+		return &types.Header{Number: big.NewInt(int64(number)), Extra: epochHeader().Extra}, nil
+	}
+	header := func(number uint64) *types.Header {
+		attestation, err := rlp.EncodeToBytes(ts.attestation(0b0001, number-2, number-1, common.BigToHash(big.NewInt(int64(number-1)))))
+		ts.Require().NoError(err)
+		extra := append(make([]byte, extraVanity), attestation...)
+		return &types.Header{Number: big.NewInt(int64(number)), Extra: append(extra, make([]byte, extraSeal)...)}
+	}
+	ctx := context.Background()
+	forkSpecs := GetForkParameters(Localnet)
+	detector := NewVoteDetector()
+
+	_, err := detector.ObserveHeader(ctx, headerFn, header(2001), forkSpecs)
+	ts.Require().NoError(err)
+	ts.Require().NotZero(queried)
+
+	// the validator sets of the epoch are reused
+	queried = 0
+	for number := uint64(2002); number < 3000; number += 100 {
+		_, err = detector.ObserveHeader(ctx, headerFn, header(number), forkSpecs)
+		ts.Require().NoError(err)
+	}
+	ts.Require().Zero(queried)
+
+	// the next epoch is queried
+	_, err = detector.ObserveHeader(ctx, headerFn, header(3001), forkSpecs)
+	ts.Require().NoError(err)
+	ts.Require().NotZero(queried)
+}
+
+func (ts *VoteDetectorTestSuite) TestObserveHeaderAtCheckpoint() {
+	// The validator set of epoch 1000 has 4 validators, so the set of epoch 2000 takes effect at 2003
+	current := make([]byte, validatorBytesLength)
+	current[0] = 9
+	epochExtra := func(validators Validators) []byte {
+		extra := append(make([]byte, extraVanity), byte(len(validators)))
+		for _, validator := range validators {
+			extra = append(extra, validator...)
+		}
+		// turn length and the empty vote attestation
+		extra = append(extra, 1, 0)
+		return append(extra, make([]byte, extraSeal)...)
+	}
+	headerFn := func(_ context.Context, number uint64) (*types.Header, error) {
+		extra := epochExtra(ts.validators)
+		if number == 2000 {
+			extra = epochExtra(Validators{current})
+		}
+		return &types.Header{Number: big.NewInt(int64(number)), Extra: extra}, nil
+	}
+	header := func(targetHash common.Hash) *types.Header {
+		attestation, err := rlp.EncodeToBytes(ts.attestation(0b1000, 2001, 2002, targetHash))
+		ts.Require().NoError(err)
+		extra := append(make([]byte, extraVanity), attestation...)
+		return &types.Header{Number: big.NewInt(2003), Extra: append(extra, make([]byte, extraSeal)...)}
+	}
+	ctx := context.Background()
+	forkSpecs := GetForkParameters(Localnet)
+	detector := NewVoteDetector()
+
+	// The votes in the checkpoint block are cast by the validator set of the previous epoch
+	violations, err := detector.ObserveHeader(ctx, headerFn, header(common.HexToHash("0x01")), forkSpecs)
+	ts.Require().NoError(err)
+	ts.Require().Empty(violations)
+	violations, err = detector.ObserveHeader(ctx, headerFn, header(common.HexToHash("0x02")), forkSpecs)
+	ts.Require().NoError(err)
+	ts.Require().Len(violations, 1)
+	ts.Require().Equal(DoubleVote, violations[0].Type)
+	ts.Require().Equal(common.BytesToAddress(ts.validators[3][:common.AddressLength]), violations[0].Validator)
+}
@@ -0,0 +1,330 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/datachainlab/ethereum-ibc-relay-chain/pkg/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/hyperledger-labs/yui-relayer/log"
+)
+
+// Watchtower follows the finalized headers of the self chain through several independent RPC endpoints.
+// When two endpoints finalize different headers at the same height, it builds a Misbehaviour
+// and submits it to the Parlia client on the counterparty chain.
+type Watchtower struct {
+	sources      []*watchtowerSource
+	counterparty core.Chain
+	votes        *VoteDetector
+	// voteObservedHeights are the last heights of each source whose vote attestation has been observed
+	voteObservedHeights map[string]uint64
+	// submittedHeights are the conflicting heights whose misbehaviour has been submitted
+	submittedHeights map[uint64]bool
+}
+
+// voteRetentionBlocks is the number of blocks for which votes are kept to detect surround votes
+const voteRetentionBlocks = 1000
+
+type watchtowerSource struct {
+	name   string
+	prover *Prover
+}
+
+// DetectedMisbehaviour is the evidence found by the Watchtower.
+type DetectedMisbehaviour struct {
+	// Height is the height at which the sources finalized different headers
+	Height uint64
+	// Sources are the names of the sources that finalized Header_1 and Header_2 respectively
+	Sources [2]string
+	// Updates are the headers to be submitted before Misbehaviour so that the counterparty has its trusted height
+	Updates      []core.Header
+	Misbehaviour *Misbehaviour
+}
+
+// NewWatchtower returns a Watchtower that compares the RPC endpoint of the prover with `rpcAddrs`.
+func NewWatchtower(prover *Prover, counterparty core.Chain, rpcAddrs []string) (*Watchtower, error) {
+	sources := []*watchtowerSource{{name: prover.chain.ChainID(), prover: prover}}
+	for _, rpcAddr := range rpcAddrs {
+		cl, err := client.NewETHClient(rpcAddr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s: %+v", rpcAddr, err)
+		}
+		sources = append(sources, &watchtowerSource{
+			name:   rpcAddr,
+			prover: &Prover{chain: newSourceChain(prover.chain, cl), config: prover.config},
+		})
+	}
+	if len(sources) < 2 {
+		return nil, fmt.Errorf("at least one additional rpc address is required")
+	}
+	return &Watchtower{sources: sources, counterparty: counterparty, votes: NewVoteDetector(), voteObservedHeights: make(map[string]uint64), submittedHeights: make(map[uint64]bool)}, nil
+}
+
+// Run checks misbehaviour every `interval` until ctx is done.
+// Detected misbehaviour is submitted to the counterparty chain.
+func (w *Watchtower) Run(ctx context.Context, interval time.Duration) error {
+	logger := log.GetLogger().WithModule("parlia-watchtower")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		violations, err := w.CheckVotes(ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, "failed to check votes", err)
+		}
+		for _, violation := range violations {
+			logger.WarnContext(ctx, "conflicting votes found", "violation", violation.String())
+		}
+		detected, err := w.CheckMisbehaviour(ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, "failed to check misbehaviour", err)
+		} else if detected != nil {
+			if err = w.Submit(ctx, detected); err != nil {
+				logger.ErrorContext(ctx, "failed to submit misbehaviour", err, "height", detected.Height)
+			} else {
+				logger.InfoContext(ctx, "misbehaviour submitted", "height", detected.Height, "sources", detected.Sources)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckMisbehaviour compares the finalized headers of all the sources at the lowest finalized height among them.
+// It returns nil if no conflict is found, the misbehaviour at the height has been submitted or the client is already frozen.
+func (w *Watchtower) CheckMisbehaviour(ctx context.Context) (*DetectedMisbehaviour, error) {
+	logger := log.GetLogger().WithModule("parlia-watchtower")
+	forkSpecs := w.sources[0].prover.getForkParameters()
+
+	type progress struct {
+		source          *watchtowerSource
+		latestHeight    uint64
+		finalizedHeight uint64
+	}
+	var available []progress
+	for _, source := range w.sources {
+		latestHeight, err := source.prover.chain.LatestHeight(ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, "failed to get latest height", err, "source", source.name)
+			continue
+		}
+		finalizedHeight, _, err := queryLatestFinalizedHeader(ctx, source.prover.chain.Header, latestHeight.GetRevisionHeight(), forkSpecs, source.prover.finalitySearchDepth())
+		if err != nil {
+			logger.ErrorContext(ctx, "failed to get latest finalized header", err, "source", source.name)
+			continue
+		}
+		available = append(available, progress{source: source, latestHeight: latestHeight.GetRevisionHeight(), finalizedHeight: finalizedHeight})
+	}
+	if len(available) < 2 {
+		return nil, fmt.Errorf("not enough available sources: %d", len(available))
+	}
+	height := available[0].finalizedHeight
+	for _, p := range available[1:] {
+		height = minUint64(height, p.finalizedHeight)
+	}
+
+	var observations []*finalizedObservation
+	for _, p := range available {
+		ethHeaders, err := queryFinalizedHeader(ctx, p.source.prover.chain.Header, height, p.latestHeight, forkSpecs)
+		if err != nil {
+			logger.ErrorContext(ctx, "failed to get finalized header", err, "source", p.source.name, "height", height)
+			continue
+		}
+		if ethHeaders == nil {
+			continue
+		}
+		target, err := ethHeaders[0].decode()
+		if err != nil {
+			return nil, err
+		}
+		observations = append(observations, &finalizedObservation{source: p.source, hash: target.Hash(), headers: ethHeaders})
+	}
+	first, second := findConflictingObservations(observations)
+	if first == nil {
+		logger.DebugContext(ctx, "no misbehaviour found", "height", height, "sources", len(observations))
+		return nil, nil
+	}
+	logger.WarnContext(ctx, "conflicting finalized headers found", "height", height,
+		"source1", first.source.name, "hash1", first.hash, "source2", second.source.name, "hash2", second.hash)
+	if w.submittedHeights[height] {
+		logger.DebugContext(ctx, "misbehaviour already submitted", "height", height)
+		return nil, nil
+	}
+	return w.buildMisbehaviour(ctx, height, first, second)
+}
+
+// CheckVotes observes the vote attestations of the blocks produced since the last call on every source and
+// returns the validators that signed conflicting votes.
+func (w *Watchtower) CheckVotes(ctx context.Context) ([]*VoteViolation, error) {
+	var violations []*VoteViolation
+	var lowest uint64
+	for i, source := range w.sources {
+		found, latest, err := w.checkSourceVotes(ctx, source)
+		violations = append(violations, found...)
+		if err != nil {
+			return violations, fmt.Errorf("source=%s, %w", source.name, err)
+		}
+		if i == 0 {
+			lowest = latest
+		}
+		lowest = minUint64(lowest, latest)
+	}
+	if lowest > voteRetentionBlocks {
+		w.votes.Prune(lowest - voteRetentionBlocks)
+	}
+	return violations, nil
+}
+
+func (w *Watchtower) checkSourceVotes(ctx context.Context, source *watchtowerSource) ([]*VoteViolation, uint64, error) {
+	chain := source.prover.chain
+	latestHeight, err := chain.LatestHeight(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	latest := latestHeight.GetRevisionHeight()
+	observed := w.voteObservedHeights[source.name]
+	if observed == 0 || latest-observed > voteRetentionBlocks {
+		observed = latest - minUint64(latest, voteRetentionBlocks)
+	}
+	var violations []*VoteViolation
+	for height := observed + 1; height <= latest; height++ {
+		header, err := chain.Header(ctx, height)
+		if err != nil {
+			return violations, latest, err
+		}
+		found, err := w.votes.ObserveHeader(ctx, chain.Header, header, source.prover.getForkParameters())
+		if err != nil {
+			return violations, latest, err
+		}
+		violations = append(violations, found...)
+		w.voteObservedHeights[source.name] = height
+	}
+	return violations, latest, nil
+}
+
+// Submit sends the headers required to trust the Misbehaviour and the Misbehaviour itself to the counterparty chain.
+// The height is recorded once they are sent, so the same misbehaviour is not submitted again.
+func (w *Watchtower) Submit(ctx context.Context, detected *DetectedMisbehaviour) error {
+	signer, err := w.counterparty.GetAddress()
+	if err != nil {
+		return err
+	}
+	var msgs []sdk.Msg
+	for _, h := range detected.Updates {
+		msg, err := clienttypes.NewMsgUpdateClient(w.counterparty.Path().ClientID, h, signer.String())
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, msg)
+	}
+	msg, err := clienttypes.NewMsgUpdateClient(w.counterparty.Path().ClientID, detected.Misbehaviour, signer.String())
+	if err != nil {
+		return err
+	}
+	msgs = append(msgs, msg)
+	if _, err = w.counterparty.SendMsgs(ctx, msgs); err != nil {
+		return err
+	}
+	w.submittedHeights[detected.Height] = true
+	return nil
+}
+
+func (w *Watchtower) buildMisbehaviour(ctx context.Context, height uint64, first, second *finalizedObservation) (*DetectedMisbehaviour, error) {
+	cpHeight, err := w.counterparty.LatestHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := w.counterparty.QueryClientState(core.NewQueryContext(ctx, cpHeight))
+	if err != nil {
+		return nil, fmt.Errorf("no client state found : buildMisbehaviour: height = %d, %+v", cpHeight.GetRevisionHeight(), err)
+	}
+	var cs exported.ClientState
+	if err = w.counterparty.Codec().UnpackAny(res.ClientState, &cs); err != nil {
+		return nil, err
+	}
+	// The frozen client never accepts the misbehaviour again
+	if clientState, ok := cs.(*ClientState); ok && clientState.Frozen {
+		log.GetLogger().WithModule("parlia-watchtower").InfoContext(ctx, "client is already frozen", "clientID", w.counterparty.Path().ClientID, "height", height)
+		return nil, nil
+	}
+	trustedHeight := cs.GetLatestHeight()
+	if height <= trustedHeight.GetRevisionHeight() {
+		return nil, fmt.Errorf("conflicting height %d is not greater than the trusted height %d", height, trustedHeight.GetRevisionHeight())
+	}
+
+	headers1, err := first.headersForUpdate(ctx, height, trustedHeight)
+	if err != nil {
+		return nil, err
+	}
+	headers2, err := second.headersForUpdate(ctx, height, trustedHeight)
+	if err != nil {
+		return nil, err
+	}
+	// Both headers must be verified against the same trusted height on the counterparty.
+	updates1, updates2 := headers1[:len(headers1)-1], headers2[:len(headers2)-1]
+	if len(updates1) != len(updates2) {
+		return nil, fmt.Errorf("sources require different intermediate headers: %s=%d, %s=%d", first.source.name, len(updates1), second.source.name, len(updates2))
+	}
+	for i := range updates1 {
+		h1, err := updates1[i].(*Header).Target()
+		if err != nil {
+			return nil, err
+		}
+		h2, err := updates2[i].(*Header).Target()
+		if err != nil {
+			return nil, err
+		}
+		if h1.Hash() != h2.Hash() {
+			return nil, fmt.Errorf("sources conflict at the intermediate height %d", h1.Number.Uint64())
+		}
+	}
+	misbehaviour, err := BuildMisbehaviour(w.counterparty.Path().ClientID, headers1[len(headers1)-1].(*Header), headers2[len(headers2)-1].(*Header))
+	if err != nil {
+		return nil, err
+	}
+	return &DetectedMisbehaviour{
+		Height:       height,
+		Sources:      [2]string{first.source.name, second.source.name},
+		Updates:      updates1,
+		Misbehaviour: misbehaviour,
+	}, nil
+}
+
+type finalizedObservation struct {
+	source  *watchtowerSource
+	hash    common.Hash
+	headers []*ETHHeader
+}
+
+// headersForUpdate returns the headers to update the client from `trustedHeight` to the observed finalized header.
+func (o *finalizedObservation) headersForUpdate(ctx context.Context, height uint64, trustedHeight exported.Height) ([]core.Header, error) {
+	verifiable, err := o.source.prover.withValidators(ctx, height, o.headers)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := o.source.prover.SetupHeadersForUpdateByLatestHeight(ctx, trustedHeight, verifiable.(*Header))
+	if err != nil {
+		return nil, err
+	}
+	if len(headers) == 0 {
+		return nil, fmt.Errorf("no header to submit: source=%s, height=%d", o.source.name, height)
+	}
+	return headers, nil
+}
+
+// findConflictingObservations returns the first pair of observations whose finalized headers differ.
+func findConflictingObservations(observations []*finalizedObservation) (*finalizedObservation, *finalizedObservation) {
+	for i := 1; i < len(observations); i++ {
+		if observations[i].hash != observations[0].hash {
+			return observations[0], observations[i]
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,88 @@
+package module
+
+import (
+	"context"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockCounterparty struct {
+	core.Chain
+	sent []sdk.Msg
+}
+
+func (c *mockCounterparty) Path() *core.PathEnd {
+	return &core.PathEnd{ClientID: "xx-parlia-1"}
+}
+
+func (c *mockCounterparty) GetAddress() (sdk.AccAddress, error) {
+	return sdk.AccAddress(common.Address{}.Bytes()), nil
+}
+
+func (c *mockCounterparty) SendMsgs(_ context.Context, msgs []sdk.Msg) ([]core.MsgID, error) {
+	c.sent = append(c.sent, msgs...)
+	return nil, nil
+}
+
+type WatchtowerTestSuite struct {
+	suite.Suite
+}
+
+func TestWatchtowerTestSuite(t *testing.T) {
+	suite.Run(t, new(WatchtowerTestSuite))
+}
+
+func (ts *WatchtowerTestSuite) SetupTest() {
+	err := log.InitLogger("DEBUG", "text", "stdout", false)
+	ts.Require().NoError(err)
+}
+
+func (ts *WatchtowerTestSuite) TestFindConflictingObservations() {
+	a := &finalizedObservation{source: &watchtowerSource{name: "a"}, hash: common.HexToHash("0x01")}
+	b := &finalizedObservation{source: &watchtowerSource{name: "b"}, hash: common.HexToHash("0x01")}
+	c := &finalizedObservation{source: &watchtowerSource{name: "c"}, hash: common.HexToHash("0x02")}
+
+	first, second := findConflictingObservations([]*finalizedObservation{a, b})
+	ts.Require().Nil(first)
+	ts.Require().Nil(second)
+
+	first, second = findConflictingObservations([]*finalizedObservation{a, b, c})
+	ts.Require().Equal(a, first)
+	ts.Require().Equal(c, second)
+
+	first, second = findConflictingObservations(nil)
+	ts.Require().Nil(first)
+	ts.Require().Nil(second)
+}
+
+func (ts *WatchtowerTestSuite) TestSubmit() {
+	counterparty := &mockCounterparty{}
+	watchtower := &Watchtower{counterparty: counterparty, submittedHeights: make(map[uint64]bool)}
+
+	trustedHeight := clienttypes.NewHeight(0, 100)
+	update := &Header{TrustedHeight: &trustedHeight}
+	misbehaviour := &Misbehaviour{ClientId: "xx-parlia-1", Header_1: &Header{}, Header_2: &Header{}}
+	err := watchtower.Submit(context.Background(), &DetectedMisbehaviour{
+		Height:       200,
+		Updates:      []core.Header{update},
+		Misbehaviour: misbehaviour,
+	})
+	ts.Require().NoError(err)
+	ts.Require().Len(counterparty.sent, 2)
+
+	for i, expected := range []interface{}{update, misbehaviour} {
+		msg, ok := counterparty.sent[i].(*clienttypes.MsgUpdateClient)
+		ts.Require().True(ok)
+		ts.Require().Equal("xx-parlia-1", msg.ClientId)
+		clientMessage, err := clienttypes.UnpackClientMessage(msg.ClientMessage)
+		ts.Require().NoError(err)
+		ts.Require().Equal(expected, clientMessage)
+	}
+	ts.Require().True(watchtower.submittedHeights[200])
+}